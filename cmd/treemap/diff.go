@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/MazenAlkhatib/treemap"
+	"github.com/MazenAlkhatib/treemap/layout"
+	"github.com/MazenAlkhatib/treemap/parser"
+	"github.com/MazenAlkhatib/treemap/render"
+)
+
+const diffDoc string = `
+Compare two header-less CSV files, such as binary sizes of two releases.
+
+Boxes are sized by new sizes, or by old sizes for removed nodes.
+Boxes are colored by relative growth, from shrink or removed to growth or added, and labelled with change of size.
+Summary of the biggest changes is printed to stdout, or to stderr when treemap is written to stdout.
+
+Usage:
+  treemap diff [options] old.csv new.csv
+
+Example:
+  treemap diff -sizes 2048x1536 -output-path release old.csv new.csv
+
+Command options:
+`
+
+func runDiff(args []string) {
+	var (
+		marginBox     float64
+		paddingBox    float64
+		padding       float64
+		colorScheme   string
+		outputPath    string
+		keepLongPaths bool
+		format        string
+		noStyles      bool
+		layoutName    string
+		quiet         bool
+		top           int
+		separator     string
+		escape        string
+	)
+
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), diffDoc)
+		flags.PrintDefaults()
+	}
+
+	sizesStr := flags.String("sizes", "1024x1024", "comma-separated list of output sizes in format widthxheight (e.g., 1024x768,2048x1536)")
+	flags.Float64Var(&marginBox, "margin-box", 4, "margin between boxes")
+	flags.Float64Var(&paddingBox, "padding-box", 4, "padding between box border and content")
+	flags.Float64Var(&padding, "padding", 32, "padding around root content")
+	flags.StringVar(&colorScheme, "color", "RdBu", "diverging color scheme (RdBu, RdYlGn), growth is colored with first color of scheme")
+	flags.StringVar(&outputPath, "output-path", "treemap_diff", "The output path of the rendered image (- writes single size to stdout)")
+	flags.BoolVar(&keepLongPaths, "long-paths", false, "keep long paths when paren has single child")
+	flags.StringVar(&format, "format", "svg", "output format (svg, png, html)")
+	flags.BoolVar(&noStyles, "no-inline-styles", false, "use SVG presentation attributes instead of inline styles, for strict Content Security Policy (e.g. Jenkins)")
+	flags.StringVar(&layoutName, "layout", "squarify", "layout algorithm (squarify, slice-dice, strip, pivot), all but squarify preserve input order")
+	flags.BoolVar(&quiet, "quiet", false, "do not report progress")
+	flags.StringVar(&separator, "separator", "/", "separator of parts of paths in input, such as . for Java packages, \\ for Windows paths or :: for Rust modules")
+	flags.StringVar(&escape, "escape", "", "character that escapes separator and itself in parts of paths, such as \\ (no escaping by default)")
+	flags.IntVar(&top, "top", 10, "number of biggest changes in summary")
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	sizes, err := parseSizes(*sizesStr)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pathFormat, err := parsePathFormat(separator, escape)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	treeLayout, ok := layout.GetLayout(layoutName)
+	if !ok {
+		log.Fatalf("invalid layout: %s (expected squarify, slice-dice, strip or pivot)", layoutName)
+	}
+
+	if format != "svg" && format != "png" && format != "html" {
+		log.Fatalf("invalid format: %s (expected svg, png or html)", format)
+	}
+
+	palette, ok := render.GetPalette(colorScheme)
+	if !ok {
+		log.Fatalf("invalid color scheme: %s (expected RdBu or RdYlGn)", colorScheme)
+	}
+
+	if outputPath == "-" && len(sizes) > 1 {
+		log.Fatalf("can not write %d sizes to stdout, expected one size", len(sizes))
+	}
+
+	var progress treemap.Progress = &treemap.BarProgress{}
+	if quiet {
+		progress = treemap.NopProgress{}
+	}
+
+	parser := parser.CSVTreeParser{Format: pathFormat, Progress: progress}
+	sizeImputer := treemap.SumSizeImputer{EmptyLeafSize: 1, Progress: progress}
+
+	var trees [2]*treemap.Tree
+	for i, fileName := range flags.Args() {
+		tree, err := parser.ParseFile(fileName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "can not parse %s: %v\n", fileName, err)
+			os.Exit(1)
+		}
+		sizeImputer.ImputeSize(*tree)
+		trees[i] = tree
+	}
+
+	diff := treemap.DiffTrees(*trees[0], *trees[1])
+	tree := renderedDiffTree(diff, keepLongPaths, progress)
+
+	uiBuilder := render.UITreeMapBuilder{
+		// palette is reversed, so that growth has first color, such as red in RdBu
+		Colorer:     render.HeatColorer{Palette: palette.Reversed(), MinHeat: -1, MaxHeat: 1},
+		BorderColor: grey,
+		Layout:      treeLayout,
+		Progress:    progress,
+	}
+
+	for _, size := range sizes {
+		renderTreemapStreaming(&tree, nil, size.w, size.h, uiBuilder, outputPath, format, layoutName, noStyles, marginBox, paddingBox, padding)
+	}
+
+	out := os.Stdout
+	if outputPath == "-" {
+		out = os.Stderr
+	}
+	if err := writeDiffSummary(out, diff, top); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// renderedDiffTree is copy of tree of diff with collapsed long paths and labels with change of size,
+// tree of diff is not changed, so that its leaves are summarized
+func renderedDiffTree(diff treemap.TreeDiff, keepLongPaths bool, progress treemap.Progress) treemap.Tree {
+	tree := diff.Tree
+	tree.Nodes = make(map[string]treemap.Node, len(diff.Tree.Nodes))
+	for k, v := range diff.Tree.Nodes {
+		tree.Nodes[k] = v
+	}
+	tree.To = make(map[string][]string, len(diff.Tree.To))
+	for k, v := range diff.Tree.To {
+		tree.To[k] = append([]string(nil), v...)
+	}
+
+	if !keepLongPaths {
+		treemap.CollapseLongPathsContext(context.Background(), &tree, progress)
+	}
+
+	labelWithDelta(tree, diff)
+	return tree
+}
+
+// labelWithDelta adds change of size to names of changed nodes
+func labelWithDelta(tree treemap.Tree, diff treemap.TreeDiff) {
+	for key, node := range tree.Nodes {
+		// collapsed nodes have path of their last child, which has same change
+		c := diff.Nodes[node.Path]
+		if node.Name == "" || c.Delta() == 0 {
+			continue
+		}
+		node.Name = node.Name + " " + signedSize(c.Delta())
+		tree.Nodes[key] = node
+	}
+}
+
+func writeDiffSummary(w io.Writer, diff treemap.TreeDiff, top int) error {
+	out := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	for _, c := range diff.BiggestChanges(top) {
+		var status string
+		switch {
+		case c.IsAdded():
+			status = "added"
+		case c.IsRemoved():
+			status = "removed"
+		}
+		fmt.Fprintf(out, "%s\t%s\t->\t%s\t%s\t  %s\n", status, render.HumanizeSize(c.Old), render.HumanizeSize(c.New), signedSize(c.Delta()), c.Path)
+	}
+
+	total := diff.Nodes[diff.Tree.Root]
+	fmt.Fprintf(out, "total\t%s\t->\t%s\t%s\t\n", render.HumanizeSize(total.Old), render.HumanizeSize(total.New), signedSize(total.Delta()))
+
+	return out.Flush()
+}
+
+// signedSize is humanized size with sign for positive sizes too, such as +1.5k
+func signedSize(v float64) string {
+	if v > 0 {
+		return "+" + render.HumanizeSize(v)
+	}
+	return render.HumanizeSize(v)
+}
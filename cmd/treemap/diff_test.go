@@ -0,0 +1,42 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MazenAlkhatib/treemap"
+	"github.com/MazenAlkhatib/treemap/parser"
+)
+
+func TestDiffSummaryAfterCollapse(t *testing.T) {
+	var trees [2]*treemap.Tree
+	for i, in := range []string{"a/b/c,5\na/b/d,3\n", "a/b/c,7\na/b/e,3\n"} {
+		tree, err := (&parser.CSVTreeParser{}).ParseReader(strings.NewReader(in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		treemap.SumSizeImputer{EmptyLeafSize: 1}.ImputeSize(*tree)
+		trees[i] = tree
+	}
+
+	diff := treemap.DiffTrees(*trees[0], *trees[1])
+	tree := renderedDiffTree(diff, false, nil)
+	if _, ok := tree.Nodes["a/b"]; ok {
+		t.Errorf("long path is not collapsed")
+	}
+
+	var out strings.Builder
+	if err := writeDiffSummary(&out, diff, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if fields := strings.Fields(line); fields[0] != "total" {
+			paths = append(paths, fields[len(fields)-1])
+		}
+	}
+	if exp, got := "a/b/d a/b/e a/b/c", strings.Join(paths, " "); exp != got {
+		t.Errorf("exp(%s) != got(%s)\n%s", exp, got, out.String())
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+)
+
+// inputFormats are values of -input-format, auto detects all but edges
+var inputFormats = []string{"auto", "csv", "json", "jsonl", "edges", "coverage", "pprof"}
+
+// sniffBytes is how much of input is read ahead for detecting its format
+const sniffBytes = 4096
+
+// defaultComma is field delimiter of input by extension of file, tab for TSV and comma otherwise
+func defaultComma(name string) string {
+	if strings.ToLower(filepath.Ext(name)) == ".tsv" {
+		return "tab"
+	}
+	return ","
+}
+
+// detectInputFormat detects format by extension of file, or by content when extension is not known.
+// Content starting with object is JSON Lines when its first line is record with path, otherwise JSON.
+// Content starting with mode line is coverage profile of go test, and gzipped content is pprof profile.
+func detectInputFormat(name string, r *bufio.Reader) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return "json"
+	case ".jsonl", ".ndjson":
+		return "jsonl"
+	case ".csv", ".tsv":
+		return "csv"
+	case ".pprof", ".prof":
+		return "pprof"
+	}
+
+	// error is not relevant, peeked bytes are all there is
+	head, _ := r.Peek(sniffBytes)
+	if bytes.HasPrefix(head, []byte{0x1f, 0x8b}) {
+		return "pprof"
+	}
+	head = bytes.TrimLeft(bytes.TrimPrefix(head, []byte("\ufeff")), " \t\r\n")
+	if bytes.HasPrefix(head, []byte("mode: ")) {
+		return "coverage"
+	}
+	if len(head) == 0 || head[0] != '{' {
+		return "csv"
+	}
+
+	line := head
+	if i := bytes.IndexByte(head, '\n'); i >= 0 {
+		line = head[:i]
+	}
+	var record map[string]json.RawMessage
+	if err := json.Unmarshal(line, &record); err == nil {
+		if _, ok := record["path"]; ok {
+			return "jsonl"
+		}
+	}
+	return "json"
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func TestDefaultComma(t *testing.T) {
+	for name, exp := range map[string]string{"a.tsv": "tab", "a.TSV": "tab", "a.csv": ",", "": ","} {
+		if got := defaultComma(name); got != exp {
+			t.Errorf("%s: exp(%s) != got(%s)", name, exp, got)
+		}
+	}
+}
+
+func TestDetectInputFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		file   string
+		in     string
+		expFmt string
+	}{
+		{name: "json by extension", file: "a.JSON", in: "a,1\n", expFmt: "json"},
+		{name: "json lines by extension", file: "a.jsonl", in: "a,1\n", expFmt: "jsonl"},
+		{name: "ndjson by extension", file: "a.ndjson", expFmt: "jsonl"},
+		{name: "csv by extension", file: "a.csv", in: `{"path": "a"}`, expFmt: "csv"},
+		{name: "tsv by extension", file: "a.tsv", expFmt: "csv"},
+		{name: "pprof by extension", file: "cpu.pprof", expFmt: "pprof"},
+		{name: "json lines by path in first line", file: "-", in: `{"path": "a", "size": 1}` + "\n" + `{"path": "b"}`, expFmt: "jsonl"},
+		{name: "json lines without newline", in: `{"path": "a", "size": 1}`, expFmt: "jsonl"},
+		{name: "d3 object", in: `{"name": "a", "children": [{"name": "b", "size": 1}]}`, expFmt: "json"},
+		{name: "d3 object on many lines", in: "{\n\t\"name\": \"a\"\n}\n", expFmt: "json"},
+		{name: "json lines with byte order mark", in: "\ufeff" + `{"path": "a"}`, expFmt: "jsonl"},
+		{name: "d3 object with byte order mark and spaces", in: "\ufeff \n" + `{"name": "a"}`, expFmt: "json"},
+		{name: "csv with byte order mark", in: "\ufeffpath,size\na,1\n", expFmt: "csv"},
+		{name: "csv", in: "a/b,1\n", expFmt: "csv"},
+		{name: "coverage profile", in: "mode: set\na.go:1.1,2.2 1 1\n", expFmt: "coverage"},
+		{name: "gzipped pprof", in: "\x1f\x8b\x08", expFmt: "pprof"},
+		{name: "empty", in: "", expFmt: "csv"},
+		{name: "only spaces", in: " \n", expFmt: "csv"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := bufio.NewReaderSize(strings.NewReader(tc.in), sniffBytes)
+			if got := detectInputFormat(tc.file, r); got != tc.expFmt {
+				t.Errorf("exp(%s) != got(%s)", tc.expFmt, got)
+			}
+
+			// detection does not consume input
+			if rest, _ := r.Peek(len(tc.in)); string(rest) != tc.in {
+				t.Errorf("input is consumed: exp(%q) != got(%q)", tc.in, rest)
+			}
+		})
+	}
+}
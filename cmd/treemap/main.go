@@ -1,18 +1,25 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"image/color"
+	"io"
 	"log"
 	"os"
 	"runtime"
 	"runtime/debug"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/MazenAlkhatib/treemap"
+	"github.com/MazenAlkhatib/treemap/layout"
 	"github.com/MazenAlkhatib/treemap/parser"
 	"github.com/MazenAlkhatib/treemap/render"
 )
@@ -22,9 +29,18 @@ Generate treemaps from file in header-less CSV.
 
 Usage:
   treemap [options] -input data.csv
+  cat data.csv | treemap [options] -output-path - > treemap.svg
+  treemap -dir ./src -disk-usage -gitignore
+  treemap -binary ./app
+  treemap diff [options] old.csv new.csv
 
 Input format:
-  /delimitered/path,size
+  /delimitered/path,size,heat
+  {"name":"root","children":[{"name":"leaf","size":1,"heat":2}]} (JSON)
+  {"path":"/delimitered/path","size":1,"heat":2} (JSON Lines)
+  id,parent_id,size,label (with -input-format edges)
+  go test -coverprofile output, sized by statements and colored by coverage
+  pprof profile, such as from go test -cpuprofile, sized by sample type
 
 Example:
   treemap -input data.csv -sizes "1024x768,2048x1536" -output-path output
@@ -37,8 +53,12 @@ var grey = color.RGBA{128, 128, 128, 255}
 func main() {
 	debug.SetGCPercent(20)
 
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		runDiff(os.Args[2:])
+		return
+	}
+
 	var (
-		sizes         []struct{ w, h float64 }
 		marginBox     float64
 		paddingBox    float64
 		padding       float64
@@ -47,6 +67,32 @@ func main() {
 		outputPath    string
 		keepLongPaths bool
 		inputFile     string
+		heatDomain    string
+		format        string
+		noStyles      bool
+		layoutName    string
+		compact       bool
+		quiet         bool
+		topN          int
+		minShare      float64
+		maxDepth      int
+		descendants   bool
+		rootPath      string
+		filter        treemap.PathFilter
+		separator     string
+		escape        string
+		comma         string
+		columns       parser.CSVColumns
+		csvHeader     bool
+		inputFormat   string
+		dirPath       string
+		diskUsage     bool
+		symlinks      string
+		hidden        bool
+		gitignore     bool
+		binaryPath    string
+		sampleType    string
+		byFunction    bool
 	)
 
 	flag.Usage = func() {
@@ -59,63 +105,242 @@ func main() {
 	flag.Float64Var(&marginBox, "margin-box", 4, "margin between boxes")
 	flag.Float64Var(&paddingBox, "padding-box", 4, "padding between box border and content")
 	flag.Float64Var(&padding, "padding", 32, "padding around root content")
-	flag.StringVar(&colorScheme, "color", "balance", "color scheme (RdBu, balance, none)")
+	flag.StringVar(&colorScheme, "color", "balance", "color scheme (RdBu, RdYlGn, balance, none)")
 	flag.StringVar(&colorBorder, "color-border", "auto", "color of borders (light, dark, auto)")
-	flag.StringVar(&outputPath, "output-path", "treemap", "The output path of the rendered image")
+	flag.StringVar(&outputPath, "output-path", "treemap", "The output path of the rendered image (- writes single size to stdout)")
 	flag.BoolVar(&keepLongPaths, "long-paths", false, "keep long paths when paren has single child")
-	flag.StringVar(&inputFile, "input", "", "Input CSV file path (if not provided, reads from stdin)")
+	flag.StringVar(&separator, "separator", "/", "separator of parts of paths in input, such as . for Java packages, \\ for Windows paths or :: for Rust modules")
+	flag.StringVar(&escape, "escape", "", "character that escapes separator and itself in parts of paths, such as \\ (no escaping by default)")
+	flag.StringVar(&comma, "comma", ",", "field delimiter of input, such as \\t or tab for TSV (default is tab for .tsv input)")
+	flag.StringVar(&columns.Path, "path-col", "", "name in header or zero-based index of path column (default is first column of header-less input)")
+	flag.StringVar(&columns.Size, "size-col", "", "name in header or zero-based index of size column, used when path-col is set")
+	flag.StringVar(&columns.Heat, "heat-col", "", "name in header or zero-based index of heat column, used when path-col is set")
+	flag.StringVar(&columns.Label, "label-col", "", "name in header or zero-based index of column with names of nodes, used when path-col is set")
+	flag.BoolVar(&csvHeader, "header", false, "first row of CSV is header, needed when columns are mapped by index")
+	flag.StringVar(&inputFile, "input", "", "Input file path (if not provided, reads from stdin)")
+	flag.StringVar(&inputFormat, "input-format", "auto", "input format (auto, csv, json, jsonl, edges, coverage, pprof), auto detects all but edges by extension of input or by its content")
+	flag.StringVar(&dirPath, "dir", "", "walk directory instead of reading input, with sizes of files")
+	flag.BoolVar(&diskUsage, "disk-usage", false, "size files of dir by space allocated on disk as in du (default is apparent size as in ls -l)")
+	flag.StringVar(&symlinks, "symlinks", "skip", "symbolic links in dir (skip, count size of link, follow)")
+	flag.BoolVar(&hidden, "hidden", false, "include files and directories in dir with names starting with dot")
+	flag.BoolVar(&gitignore, "gitignore", false, "exclude files and directories in dir matching .gitignore files")
+	flag.StringVar(&binaryPath, "binary", "", "read sizes of symbols of Go executable (ELF, Mach-O or PE) instead of reading input, by package paths")
+	flag.StringVar(&sampleType, "sample-type", "", "sample type of pprof profile used as size, such as cpu, alloc_space or inuse_space (default is default sample type of profile)")
+	flag.BoolVar(&byFunction, "by-function", false, "tree of pprof profile by package paths and functions instead of call stacks")
+	flag.StringVar(&layoutName, "layout", "squarify", "layout algorithm (squarify, slice-dice, strip, pivot), all but squarify preserve input order")
+	flag.BoolVar(&quiet, "quiet", false, "do not report progress")
+	flag.IntVar(&topN, "top-n", 0, "keep at most N largest children of each node and merge the rest into \"Other (k items)\" node (0 keeps all)")
+	flag.Float64Var(&minShare, "min-share", 0, "merge children smaller than this share of sum of sizes of all children of their parent, such as 0.01, into \"Other (k items)\" node (0 keeps all)")
+	flag.IntVar(&maxDepth, "max-depth", 0, "render nodes at this depth as leaves sized by their whole subtree, root is at depth 1 (0 renders all levels)")
+	flag.BoolVar(&descendants, "show-descendants", false, "add number of descendants to titles of nodes cut off by max-depth")
+	flag.StringVar(&rootPath, "root", "", "render only subtree of node with this path, with breadcrumb of its ancestors at the top")
+	flag.Var((*patternsFlag)(&filter.Include), "include", "keep only nodes matching glob or regular expression prefixed by regexp:, with their ancestors and descendants, repeatable")
+	flag.Var((*patternsFlag)(&filter.Exclude), "exclude", "remove nodes matching glob or regular expression prefixed by regexp:, with their descendants, repeatable (e.g. -exclude vendor -exclude '*_test.go')")
+	flag.BoolVar(&compact, "compact", false, "use memory efficient tree for large inputs (html format is not supported)")
+	flag.StringVar(&format, "format", "svg", "output format (svg, png, html)")
+	flag.BoolVar(&noStyles, "no-inline-styles", false, "use SVG presentation attributes instead of inline styles, for strict Content Security Policy (e.g. Jenkins)")
+	flag.StringVar(&heatDomain, "heat-domain", "", "min and max heat for palette color schemes in format min,max (default is min and max heat in input)")
 	flag.Parse()
 
-	// Parse size pairs
-	sizeStrs := strings.Split(*sizesStr, ",")
-	sizes = make([]struct{ w, h float64 }, len(sizeStrs))
+	sizes, err := parseSizes(*sizesStr)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	for i, sizeStr := range sizeStrs {
-		parts := strings.Split(strings.TrimSpace(sizeStr), "x")
-		if len(parts) != 2 {
-			log.Fatalf("invalid size format: %s (expected widthxheight)", sizeStr)
-		}
+	pathFormat, err := parsePathFormat(separator, escape)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-		w, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
-		if err != nil {
-			log.Fatalf("invalid width value: %v", err)
+	// TSV is read with tab, unless comma is set
+	isCommaSet := false
+	flag.Visit(func(f *flag.Flag) { isCommaSet = isCommaSet || f.Name == "comma" })
+	if !isCommaSet {
+		comma = defaultComma(inputFile)
+	}
+
+	commaRune, err := parseComma(comma)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	symlinkPolicy, err := parser.ParseSymlinkPolicy(symlinks)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if (dirPath != "" && (binaryPath != "" || inputFile != "")) || (binaryPath != "" && inputFile != "") {
+		log.Fatalf("dir, binary and input can not be used together")
+	}
+
+	treeLayout, ok := layout.GetLayout(layoutName)
+	if !ok {
+		log.Fatalf("invalid layout: %s (expected squarify, slice-dice, strip or pivot)", layoutName)
+	}
+
+	if format != "svg" && format != "png" && format != "html" {
+		log.Fatalf("invalid format: %s (expected svg, png or html)", format)
+	}
+
+	if !slices.Contains(inputFormats, inputFormat) {
+		log.Fatalf("invalid input format: %s (expected %s)", inputFormat, strings.Join(inputFormats, ", "))
+	}
+
+	if compact && format == "html" {
+		log.Fatalf("html format is not supported for compact tree")
+	}
+
+	if compact && (topN > 0 || minShare > 0) {
+		log.Fatalf("top-n and min-share are not supported for compact tree")
+	}
+
+	if compact && rootPath != "" {
+		log.Fatalf("root is not supported for compact tree")
+	}
+
+	if compact && (len(filter.Include) > 0 || len(filter.Exclude) > 0) {
+		log.Fatalf("include and exclude are not supported for compact tree")
+	}
+
+	if compact && columns.Label != "" {
+		log.Fatalf("label-col is not supported for compact tree")
+	}
+
+	if outputPath == "-" && len(sizes) > 1 {
+		log.Fatalf("can not write %d sizes to stdout, expected one size", len(sizes))
+	}
+
+	var progress treemap.Progress = &treemap.BarProgress{}
+	if quiet {
+		progress = treemap.NopProgress{}
+	} else {
+		fmt.Fprintf(os.Stderr, "Processing has been started at %s\n", time.Now().Format("15:04:05"))
+	}
+
+	var inputReader *bufio.Reader
+	switch {
+	case dirPath != "":
+		inputFormat = "dir"
+	case binaryPath != "":
+		inputFormat = "binary"
+	default:
+		input := io.Reader(os.Stdin)
+		if inputFile != "" {
+			file, err := os.Open(inputFile)
+			if err != nil {
+				log.Fatalf("can not open input: %v", err)
+			}
+			defer file.Close()
+			input = file
 		}
-		sizes[i].w = w
+		inputReader = bufio.NewReaderSize(input, sniffBytes)
 
-		h, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
-		if err != nil {
-			log.Fatalf("invalid height value: %v", err)
+		if inputFormat == "auto" {
+			inputFormat = detectInputFormat(inputFile, inputReader)
 		}
-		sizes[i].h = h
 	}
 
-	fmt.Printf("Processing has been started at %s\n", time.Now().Format("15:04:05"))
+	if compact && (inputFormat == "json" || inputFormat == "edges" || inputFormat == "binary" || inputFormat == "coverage" || inputFormat == "pprof") {
+		log.Fatalf("%s input format is not supported for compact tree", inputFormat)
+	}
 
-	parser := parser.CSVTreeParser{}
-	var tree *treemap.Tree
-	var err error
+	// coverage is colored from red to green, unless color scheme is set
+	if inputFormat == "coverage" {
+		isSet := make(map[string]bool)
+		flag.Visit(func(f *flag.Flag) { isSet[f.Name] = true })
+		if !isSet["color"] {
+			colorScheme = "RdYlGn"
+		}
+		if !isSet["heat-domain"] {
+			heatDomain = "0,1"
+		}
+	}
 
-	tree, err = parser.ParseFile(inputFile)
+	var tree *treemap.Tree
+	var compactTree *treemap.CompactTree
+
+	switch inputFormat {
+	case "dir":
+		p := parser.DirTreeParser{DiskUsage: diskUsage, Symlinks: symlinkPolicy, Hidden: hidden, Gitignore: gitignore, Warnings: os.Stderr, Progress: progress}
+		if compact {
+			compactTree, err = p.ParseDirCompact(dirPath)
+		} else {
+			tree, err = p.ParseDir(dirPath)
+		}
+	case "binary":
+		p := parser.GoBinaryParser{Progress: progress}
+		tree, err = p.ParseFile(binaryPath)
+	case "json":
+		p := parser.JSONTreeParser{Format: pathFormat, Progress: progress}
+		tree, err = p.ParseReader(inputReader)
+	case "jsonl":
+		p := parser.JSONLinesTreeParser{Format: pathFormat, Progress: progress}
+		if compact {
+			compactTree, err = p.ParseReaderCompact(inputReader)
+		} else {
+			tree, err = p.ParseReader(inputReader)
+		}
+	case "pprof":
+		p := parser.PprofParser{SampleType: sampleType, ByFunction: byFunction, Progress: progress}
+		tree, err = p.ParseReader(inputReader)
+	case "coverage":
+		p := parser.CoverageProfileParser{Progress: progress}
+		tree, err = p.ParseReader(inputReader)
+	case "edges":
+		p := parser.EdgeListParser{Comma: commaRune, Format: pathFormat, Progress: progress}
+		tree, err = p.ParseReader(inputReader)
+	default:
+		p := parser.CSVTreeParser{Comma: commaRune, Columns: columns, Header: csvHeader, Format: pathFormat, Progress: progress}
+		if compact {
+			compactTree, err = p.ParseReaderCompact(inputReader)
+		} else {
+			tree, err = p.ParseReader(inputReader)
+		}
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "can not parse: %v\n", err)
 		os.Exit(1)
 	}
 
-	if err != nil {
-		log.Fatal(err)
-	}
-
 	// Force GC before heavy processing
 	runtime.GC()
 
-	treemap.SetNamesFromPaths(tree)
+	sizeImputer := treemap.SumSizeImputer{EmptyLeafSize: 1, Progress: progress}
+
+	if compact {
+		if !keepLongPaths {
+			treemap.CollapseLongPathsCompactContext(context.Background(), compactTree, progress)
+		}
+		sizeImputer.ImputeSizeCompact(compactTree)
+	} else {
+		// names are set by parser too, but not from paths when they are from labels or ids of edges
+		if columns.Label == "" && inputFormat != "edges" {
+			treemap.SetNamesFromPathsContext(context.Background(), tree, progress)
+		}
+
+		if err := filter.Filter(tree); err != nil {
+			log.Fatal(err)
+		}
+
+		if !keepLongPaths {
+			treemap.CollapseLongPathsContext(context.Background(), tree, progress)
+		}
+
+		sizeImputer.ImputeSize(*tree)
 
-	if !keepLongPaths {
-		treemap.CollapseLongPaths(tree)
+		if topN > 0 || minShare > 0 {
+			treemap.OtherAggregator{TopN: topN, MinShare: minShare, Progress: progress}.Aggregate(tree)
+		}
 	}
 
-	sizeImputer := treemap.SumSizeImputer{EmptyLeafSize: 1}
-	sizeImputer.ImputeSize(*tree)
+	var title string
+	if rootPath != "" {
+		ancestors, err := treemap.FocusSubtree(tree, rootPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		title = breadcrumb(tree.Format, append(ancestors, tree.Nodes[tree.Root]))
+	}
 
 	// Force GC before coloring setup
 	runtime.GC()
@@ -123,18 +348,29 @@ func main() {
 	var colorer render.Colorer
 
 	treeHueColorer := render.TreeHueColorer{
-		Offset: 0,
-		Hues:   map[string]float64{},
-		C:      0.5,
-		L:      0.5,
-		DeltaH: 10,
-		DeltaC: 0.3,
-		DeltaL: 0.1,
+		Offset:      0,
+		Hues:        map[string]float64{},
+		CompactHues: map[int32]float64{},
+		C:           0.5,
+		L:           0.5,
+		DeltaH:      10,
+		DeltaC:      0.3,
+		DeltaL:      0.1,
 	}
 
 	var borderColor color.Color
 	borderColor = color.White
 
+	palette, hasPalette := render.GetPalette(colorScheme)
+
+	var minHeat, maxHeat float64
+	var hasHeat bool
+	if compact {
+		minHeat, maxHeat, hasHeat = render.CompactHeatDomain(compactTree)
+	} else {
+		minHeat, maxHeat, hasHeat = render.HeatDomain(*tree)
+	}
+
 	switch {
 	case colorScheme == "none":
 		colorer = render.NoneColorer{}
@@ -142,10 +378,27 @@ func main() {
 	case colorScheme == "balanced":
 		colorer = treeHueColorer
 		borderColor = color.White
+	case hasPalette && hasHeat:
+		heatColorer := render.HeatColorer{Palette: palette, MinHeat: minHeat, MaxHeat: maxHeat}
+		if heatDomain != "" {
+			minHeat, maxHeat, err := parseHeatDomain(heatDomain)
+			if err != nil {
+				log.Fatal(err)
+			}
+			heatColorer.MinHeat, heatColorer.MaxHeat = minHeat, maxHeat
+		}
+		colorer = heatColorer
+	case hasPalette:
+		fmt.Fprintf(os.Stderr, "no heat in input, can not use color scheme %s\n", colorScheme)
+		colorer = treeHueColorer
 	default:
 		colorer = treeHueColorer
 	}
 
+	if _, ok := colorer.(render.CompactColorer); compact && !ok {
+		fmt.Fprintf(os.Stderr, "color scheme %s is not supported for compact tree, boxes are not colored\n", colorScheme)
+	}
+
 	switch {
 	case colorBorder == "light":
 		borderColor = color.White
@@ -156,23 +409,174 @@ func main() {
 	uiBuilder := render.UITreeMapBuilder{
 		Colorer:     colorer,
 		BorderColor: borderColor,
+		Layout:      treeLayout,
+		Progress:    progress,
+
+		MaxDepth:            maxDepth,
+		ShowDescendantCount: descendants,
+		Title:               title,
 	}
 
 	// Render for each size pair
 	for _, size := range sizes {
-		renderTreemapStreaming(tree, size.w, size.h, uiBuilder, outputPath, marginBox, paddingBox, padding)
+		renderTreemapStreaming(tree, compactTree, size.w, size.h, uiBuilder, outputPath, format, layoutName, noStyles, marginBox, paddingBox, padding)
 		runtime.GC()
 	}
 }
 
-func renderTreemapStreaming(tree *treemap.Tree, w, h float64, uiBuilder render.UITreeMapBuilder, outputPath string, marginBox, paddingBox, padding float64) {
+// patternsFlag is repeatable flag of path patterns
+type patternsFlag []treemap.PathPattern
+
+func (f *patternsFlag) String() string { return "" }
+
+func (f *patternsFlag) Set(s string) error {
+	p, err := treemap.ParsePathPattern(s)
+	if err != nil {
+		return err
+	}
+	*f = append(*f, p)
+	return nil
+}
+
+// breadcrumb joins names of nodes with their sizes, such as "a (1.2M) / b (300k)"
+func breadcrumb(format treemap.PathFormat, nodes []treemap.Node) string {
+	parts := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		parts = append(parts, fmt.Sprintf("%s (%s)", format.Unescape(node.Name), render.HumanizeSize(node.Size)))
+	}
+	return strings.Join(parts, " / ")
+}
+
+// parseComma parses single character delimiter, \t and tab are tab
+func parseComma(s string) (rune, error) {
+	switch s {
+	case `\t`, "tab":
+		return '\t', nil
+	}
+
+	r, n := utf8.DecodeRuneInString(s)
+	if n == 0 || n != len(s) {
+		return 0, fmt.Errorf("invalid comma: %s (expected single character)", s)
+	}
+	return r, nil
+}
+
+// parsePathFormat makes path format from separator and optional escape character
+func parsePathFormat(separator, escape string) (treemap.PathFormat, error) {
+	if separator == "" {
+		return treemap.PathFormat{}, errors.New("empty separator")
+	}
+
+	format := treemap.PathFormat{Separator: separator}
+	if escape != "" {
+		r, n := utf8.DecodeRuneInString(escape)
+		if n != len(escape) {
+			return treemap.PathFormat{}, fmt.Errorf("invalid escape: %s (expected single character)", escape)
+		}
+		if strings.ContainsRune(separator, r) {
+			return treemap.PathFormat{}, fmt.Errorf("invalid escape: %s (expected character that is not in separator)", escape)
+		}
+		format.Escape = r
+	}
+
+	return format, nil
+}
+
+// parseSizes parses comma-separated size pairs in format widthxheight
+func parseSizes(s string) ([]struct{ w, h float64 }, error) {
+	sizeStrs := strings.Split(s, ",")
+	sizes := make([]struct{ w, h float64 }, len(sizeStrs))
+
+	for i, sizeStr := range sizeStrs {
+		parts := strings.Split(strings.TrimSpace(sizeStr), "x")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid size format: %s (expected widthxheight)", sizeStr)
+		}
 
-	spec := uiBuilder.NewUITreeMap(*tree, w, h, marginBox, paddingBox, padding)
-	renderer := render.StreamingSVGRenderer{}
+		w, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid width value: %w", err)
+		}
+		sizes[i].w = w
+
+		h, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid height value: %w", err)
+		}
+		sizes[i].h = h
+	}
+
+	return sizes, nil
+}
+
+func parseHeatDomain(s string) (minHeat, maxHeat float64, err error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid heat domain format: %s (expected min,max)", s)
+	}
+
+	minHeat, err = strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid heat domain min value: %w", err)
+	}
+
+	maxHeat, err = strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid heat domain max value: %w", err)
+	}
+
+	return minHeat, maxHeat, nil
+}
+
+func renderTreemapStreaming(tree *treemap.Tree, compactTree *treemap.CompactTree, w, h float64, uiBuilder render.UITreeMapBuilder, outputPath, format, layoutName string, noStyles bool, marginBox, paddingBox, padding float64) {
+
+	var spec render.UIBox
+	if compactTree != nil {
+		spec = uiBuilder.NewUICompactTreeMap(compactTree, w, h, marginBox, paddingBox, padding)
+	} else {
+		spec = uiBuilder.NewUITreeMap(*tree, w, h, marginBox, paddingBox, padding)
+	}
+
+	var renderTo func(root render.UIBox, w, h float64, out io.Writer) error
+	var fileName string
+	switch format {
+	case "png":
+		renderTo = render.PNGRenderer{Progress: uiBuilder.Progress}.RenderTo
+		fileName = fmt.Sprintf("%s_%d_%d.png", outputPath, int(w), int(h))
+	case "html":
+		renderer := render.HTMLRenderer{
+			Colorer:     uiBuilder.Colorer,
+			BorderColor: uiBuilder.BorderColor,
+			Margin:      marginBox,
+			Padding:     paddingBox,
+			Layout:      layoutName,
+			Progress:    uiBuilder.Progress,
+		}
+		renderTo = func(root render.UIBox, w, h float64, out io.Writer) error {
+			return renderer.RenderTo(*tree, root, w, h, out)
+		}
+		fileName = fmt.Sprintf("%s_%d_%d.html", outputPath, int(w), int(h))
+	default:
+		renderTo = render.StreamingSVGRenderer{PresentationAttributes: noStyles, Progress: uiBuilder.Progress}.RenderStreamTo
+		fileName = fmt.Sprintf("%s_%d_%d_stream.svg", outputPath, int(w), int(h))
+	}
+
+	if outputPath == "-" {
+		if err := renderTo(spec, w, h, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error streaming to stdout: %v\n", err)
+		}
+		return
+	}
+
+	file, err := os.Create(fileName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating file: %v\n", err)
+		return
+	}
+	defer file.Close()
 
-	fileName := fmt.Sprintf("%s_%d_%d_stream.svg", outputPath, int(w), int(h))
-	if err := renderer.RenderStream(spec, w, h, fileName); err != nil {
-		fmt.Printf("Error streaming to file: %v\n", err)
+	if err := renderTo(spec, w, h, file); err != nil {
+		fmt.Fprintf(os.Stderr, "Error streaming to file: %v\n", err)
 		return
 	}
 
@@ -1,32 +1,46 @@
 package treemap
 
 import (
-	"strings"
+	"context"
 
 	"github.com/schollz/progressbar/v3"
 )
 
 // CollapseLongPaths will collapse all long chains in tree.
 func CollapseLongPaths(t *Tree) {
+	CollapseLongPathsContext(context.Background(), t, nil)
+}
+
+// CollapseLongPathsContext is same as CollapseLongPaths, but reports progress when it is not nil,
+// and stops with context error when context is done. Tree is partially collapsed then.
+func CollapseLongPathsContext(ctx context.Context, t *Tree, progress Progress) error {
 	if t == nil {
-		return
+		return nil
 	}
 
-	// Create progress bar with total number of nodes
-	bar := progressbar.Default(int64(len(t.Nodes)))
-	bar.Describe("Collapsing long paths")
+	bar := ProgressOrNop(progress)
+	bar.Start("Collapsing long paths", int64(len(t.Nodes)))
+	defer bar.Finish()
 
 	// Process nodes
-	CollapseLongPathsFromNode(t, t.Root, bar)
+	return collapseLongPathsFromNode(ctx, t, t.Root, bar)
 }
 
 // CollapseLongPathsFromNode will collapse current node into children as long as it has single child.
 // Will set name of this node to joined path from roots.
-// Will set size to this child's size.
+// Will set size and heat to this child's size and heat.
 // Expecting Name containing either single value for current node.
+// Bar is advanced for each node when it is not nil.
 func CollapseLongPathsFromNode(t *Tree, nodeName string, bar *progressbar.ProgressBar) {
+	collapseLongPathsFromNode(context.Background(), t, nodeName, &BarProgress{bar: bar})
+}
+
+func collapseLongPathsFromNode(ctx context.Context, t *Tree, nodeName string, bar Progress) error {
 	if t == nil {
-		return
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
 	bar.Add(1)
@@ -56,9 +70,11 @@ func CollapseLongPathsFromNode(t *Tree, nodeName string, bar *progressbar.Progre
 
 		// copy fields from child to current node
 		t.Nodes[nodeName] = Node{
-			Path: node.Path,
-			Name: strings.Join(parts, "/"),
-			Size: node.Size,
+			Path:    node.Path,
+			Name:    t.Format.Join(parts...),
+			Size:    node.Size,
+			Heat:    node.Heat,
+			HasHeat: node.HasHeat,
 		}
 
 		// delete last child, since it is unreachable now
@@ -68,6 +84,70 @@ func CollapseLongPathsFromNode(t *Tree, nodeName string, bar *progressbar.Progre
 
 	// recursively collapse
 	for _, node := range t.To[nodeName] {
-		CollapseLongPathsFromNode(t, node, bar)
+		if err := collapseLongPathsFromNode(ctx, t, node, bar); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CollapseLongPathsCompact will collapse all long chains in compact tree, same as CollapseLongPaths.
+// Collapsed children become unreachable.
+func CollapseLongPathsCompact(t *CompactTree) {
+	CollapseLongPathsCompactContext(context.Background(), t, nil)
+}
+
+// CollapseLongPathsCompactContext is same as CollapseLongPathsCompact, but reports progress when it is not nil,
+// and stops with context error when context is done.
+func CollapseLongPathsCompactContext(ctx context.Context, t *CompactTree, progress Progress) error {
+	if t == nil {
+		return nil
+	}
+
+	bar := ProgressOrNop(progress)
+	bar.Start("Collapsing long paths", int64(len(t.Nodes)))
+	defer bar.Finish()
+
+	que := []int32{t.Root}
+	var node int32
+	for len(que) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		node, que = que[len(que)-1], que[:len(que)-1]
+		bar.Add(1)
+
+		parts := []string{}
+		q := node
+		for t.Nodes[q].NumChildren == 1 {
+			parts = append(parts, t.Name(q))
+			q = t.ChildrenOf(q)[0]
+		}
+
+		// if we skipped some children
+		if q != node {
+			parts = append(parts, t.Name(q))
+
+			// copy fields from last child to current node
+			n, last := &t.Nodes[node], t.Nodes[q]
+			n.Name = int32(len(t.Names))
+			n.FirstChild = last.FirstChild
+			n.NumChildren = last.NumChildren
+			n.Size = last.Size
+			n.Heat = last.Heat
+			n.HasHeat = last.HasHeat
+			t.Names = append(t.Names, t.Format.Join(parts...))
+
+			// redirect edges from last child to current node
+			for _, child := range t.ChildrenOf(node) {
+				t.Nodes[child].Parent = node
+			}
+		}
+
+		que = append(que, t.ChildrenOf(node)...)
 	}
+
+	return nil
 }
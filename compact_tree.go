@@ -0,0 +1,180 @@
+package treemap
+
+// CompactTree is memory efficient tree for large inputs.
+// Nodes are identified by index, names are interned once, children of each node are contiguous in one slice.
+// Full paths are not stored and reconstructed only when needed.
+// Node with index 0 is virtual, its children are roots of input.
+// Parents always have lower index than their children.
+type CompactTree struct {
+	Names    []string // interned names
+	Nodes    []CompactNode
+	Children []int32 // children of node are Children[FirstChild:FirstChild+NumChildren]
+	Root     int32   // virtual node when there are multiple roots
+
+	Format PathFormat // how paths are split into names, "/" without escaping when not set
+}
+
+// CompactNode is node of CompactTree.
+type CompactNode struct {
+	Name        int32 // index in Names
+	Parent      int32 // -1 for virtual node
+	FirstChild  int32
+	NumChildren int32
+	Size        float64
+	Heat        float64
+	HasHeat     bool
+}
+
+// Name of node.
+func (t *CompactTree) Name(node int32) string {
+	return t.Names[t.Nodes[node].Name]
+}
+
+// ChildrenOf returns children of node.
+func (t *CompactTree) ChildrenOf(node int32) []int32 {
+	n := t.Nodes[node]
+	return t.Children[n.FirstChild : n.FirstChild+n.NumChildren]
+}
+
+// Path reconstructs full path of node from names of its ancestors.
+func (t *CompactTree) Path(node int32) string {
+	var parts []string
+	for q := node; q > 0; q = t.Nodes[q].Parent {
+		parts = append(parts, t.Name(q))
+	}
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return t.Format.Join(parts...)
+}
+
+// Tree converts to map based tree, for passes that are not supported by CompactTree.
+func (t *CompactTree) Tree() *Tree {
+	tree := &Tree{
+		Nodes:  make(map[string]Node, len(t.Nodes)),
+		To:     make(map[string][]string),
+		Format: t.Format,
+	}
+
+	var visit func(node int32, path string)
+	visit = func(node int32, path string) {
+		n := t.Nodes[node]
+		tree.Nodes[path] = Node{
+			Path:    path,
+			Name:    t.Name(node),
+			Size:    n.Size,
+			Heat:    n.Heat,
+			HasHeat: n.HasHeat,
+		}
+		for _, child := range t.ChildrenOf(node) {
+			childPath := t.Path(child)
+			tree.To[path] = append(tree.To[path], childPath)
+			visit(child, childPath)
+		}
+	}
+
+	tree.Root = t.Path(t.Root)
+	if t.Root == 0 {
+		tree.Root = "some-secret-string"
+	}
+	visit(t.Root, tree.Root)
+
+	return tree
+}
+
+// CompactTreeBuilder builds CompactTree by adding nodes one by one.
+type CompactTreeBuilder struct {
+	Format PathFormat // how paths are split into names, "/" without escaping when not set
+
+	names    map[string]int32
+	children map[compactEdge]int32
+	tree     CompactTree
+}
+
+type compactEdge struct {
+	parent int32
+	name   int32
+}
+
+func NewCompactTreeBuilder() *CompactTreeBuilder {
+	b := &CompactTreeBuilder{
+		names:    make(map[string]int32),
+		children: make(map[compactEdge]int32),
+	}
+	b.tree.Nodes = []CompactNode{{Name: b.intern(""), Parent: -1}}
+	return b
+}
+
+func (b *CompactTreeBuilder) intern(name string) int32 {
+	if id, ok := b.names[name]; ok {
+		return id
+	}
+	id := int32(len(b.tree.Names))
+	b.tree.Names = append(b.tree.Names, name)
+	b.names[name] = id
+	return id
+}
+
+// Add node by its path, adding missing parents.
+// Duplicate nodes sum their sizes and average their heat weighted by size.
+func (b *CompactTreeBuilder) Add(node Node) {
+	q := int32(0)
+	for _, part := range b.Format.Split(node.Path) {
+		edge := compactEdge{parent: q, name: b.intern(part)}
+		child, ok := b.children[edge]
+		if !ok {
+			child = int32(len(b.tree.Nodes))
+			b.tree.Nodes = append(b.tree.Nodes, CompactNode{Name: edge.name, Parent: q})
+			b.children[edge] = child
+		}
+		q = child
+	}
+
+	n := &b.tree.Nodes[q]
+	switch {
+	case n.HasHeat && node.HasHeat:
+		if total := n.Size + node.Size; total != 0 {
+			n.Heat = (n.Heat*n.Size + node.Heat*node.Size) / total
+		} else {
+			n.Heat = node.Heat
+		}
+	case node.HasHeat:
+		n.Heat = node.Heat
+		n.HasHeat = true
+	}
+	n.Size += node.Size
+}
+
+// Build makes contiguous children and finds root.
+// Builder should not be used after this.
+func (b *CompactTreeBuilder) Build() *CompactTree {
+	t := &b.tree
+	t.Format = b.Format
+	b.names, b.children = nil, nil
+
+	// counting sort of nodes by parent, children keep order in which they were added
+	offsets := make([]int32, len(t.Nodes)+1)
+	for _, n := range t.Nodes[1:] {
+		offsets[n.Parent+1]++
+	}
+	for i := 1; i < len(offsets); i++ {
+		offsets[i] += offsets[i-1]
+	}
+
+	t.Children = make([]int32, len(t.Nodes)-1)
+	for i := range t.Nodes {
+		t.Nodes[i].FirstChild = offsets[i]
+	}
+	for i, n := range t.Nodes[1:] {
+		p := &t.Nodes[n.Parent]
+		t.Children[p.FirstChild+p.NumChildren] = int32(i + 1)
+		p.NumChildren++
+	}
+
+	t.Root = 0
+	if t.Nodes[0].NumChildren == 1 {
+		t.Root = t.Children[0]
+	}
+
+	return t
+}
@@ -0,0 +1,87 @@
+package treemap
+
+import (
+	"testing"
+)
+
+func TestCompactTreeBuilder(t *testing.T) {
+	b := NewCompactTreeBuilder()
+	b.Add(Node{Path: "a/b/c", Size: 1})
+	b.Add(Node{Path: "a/d", Size: 2, Heat: 1, HasHeat: true})
+	b.Add(Node{Path: "a/b/e", Size: 3})
+	b.Add(Node{Path: "a/d", Size: 6, Heat: 5, HasHeat: true})
+	tree := b.Build()
+
+	if name := tree.Name(tree.Root); name != "a" {
+		t.Errorf("root: exp(a) != got(%s)", name)
+	}
+
+	var paths []string
+	for _, child := range tree.ChildrenOf(tree.Root) {
+		paths = append(paths, tree.Path(child))
+	}
+	if len(paths) != 2 || paths[0] != "a/b" || paths[1] != "a/d" {
+		t.Errorf("wrong children of root: %v", paths)
+	}
+
+	d := tree.Nodes[tree.ChildrenOf(tree.Root)[1]]
+	if d.Size != 8 || d.Heat != 4 || !d.HasHeat {
+		t.Errorf("duplicates are not merged: %#v", d)
+	}
+
+	// names are interned
+	if len(tree.Names) != 6 {
+		t.Errorf("exp(6) names != got(%d): %v", len(tree.Names), tree.Names)
+	}
+}
+
+func TestCompactTreeMultipleRoots(t *testing.T) {
+	b := NewCompactTreeBuilder()
+	b.Add(Node{Path: "a/b"})
+	b.Add(Node{Path: "c"})
+	tree := b.Build()
+
+	if tree.Root != 0 {
+		t.Errorf("exp virtual root, got(%d)", tree.Root)
+	}
+	if n := len(tree.ChildrenOf(tree.Root)); n != 2 {
+		t.Errorf("exp(2) roots != got(%d)", n)
+	}
+
+	converted := tree.Tree()
+	if converted.Root != "some-secret-string" || len(converted.To[converted.Root]) != 2 {
+		t.Errorf("wrong converted root: %#v", converted)
+	}
+}
+
+func TestCompactTreeCollapseAndImpute(t *testing.T) {
+	b := NewCompactTreeBuilder()
+	b.Add(Node{Path: "a/b/c/d", Size: 1, Heat: 2, HasHeat: true})
+	b.Add(Node{Path: "a/b/c/e", Size: 3, Heat: 6, HasHeat: true})
+	b.Add(Node{Path: "a/b/c/f/g"})
+	tree := b.Build()
+
+	CollapseLongPathsCompact(tree)
+	SumSizeImputer{EmptyLeafSize: 4}.ImputeSizeCompact(tree)
+
+	root := tree.Nodes[tree.Root]
+	if name := tree.Name(tree.Root); name != "a/b/c" {
+		t.Errorf("root name: exp(a/b/c) != got(%s)", name)
+	}
+	if root.Size != 8 || root.Heat != 5 || !root.HasHeat {
+		t.Errorf("wrong root: %#v", root)
+	}
+
+	children := tree.ChildrenOf(tree.Root)
+	if len(children) != 3 {
+		t.Fatalf("exp(3) children != got(%d)", len(children))
+	}
+	if path := tree.Path(children[2]); path != "a/b/c/f/g" {
+		t.Errorf("collapsed path: exp(a/b/c/f/g) != got(%s)", path)
+	}
+
+	converted := tree.Tree()
+	if n := converted.Nodes["a/b/c/f/g"]; n.Name != "f/g" || n.Size != 4 {
+		t.Errorf("wrong converted node: %#v", n)
+	}
+}
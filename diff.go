@@ -0,0 +1,182 @@
+package treemap
+
+import (
+	"math"
+	"sort"
+)
+
+// DiffNode is change of size of node between old and new tree.
+type DiffNode struct {
+	Path   string
+	Old    float64 // zero when node is not in old tree
+	New    float64 // zero when node is not in new tree
+	HasOld bool
+	HasNew bool
+}
+
+func (d DiffNode) Delta() float64 { return d.New - d.Old }
+
+func (d DiffNode) IsAdded() bool { return !d.HasOld && d.HasNew }
+
+func (d DiffNode) IsRemoved() bool { return d.HasOld && !d.HasNew }
+
+// RelativeChange is delta divided by larger of old and new size, it is in [-1, 1].
+// Removed nodes have -1, added nodes have 1, node that doubled in size has 0.5.
+func (d DiffNode) RelativeChange() float64 {
+	m := math.Max(math.Abs(d.Old), math.Abs(d.New))
+	if m == 0 {
+		return 0
+	}
+	return d.Delta() / m
+}
+
+// TreeDiff is union of old and new tree aligned by path, with change of size for each node.
+type TreeDiff struct {
+	// Tree has all nodes of both trees. Nodes are sized by new size, or by old size for removed nodes,
+	// so that removed subtrees are visible. Heat is relative change of size.
+	Tree  Tree
+	Nodes map[string]DiffNode // node identifier (path) -> change
+}
+
+// DiffTrees aligns nodes of old and new tree by path.
+// Expects sizes to be imputed in both trees, such as by SumSizeImputer.
+func DiffTrees(oldTree, newTree Tree) TreeDiff {
+	d := TreeDiff{
+		Tree: Tree{
+			Nodes:  make(map[string]Node),
+			To:     make(map[string][]string),
+			Format: newTree.Format,
+		},
+		Nodes: make(map[string]DiffNode),
+	}
+
+	// new tree first, so that order of children is as in new tree, followed by removed children
+	roots := d.addTree(newTree, true)
+	roots = uniqueInOrder(append(roots, d.addTree(oldTree, false)...))
+
+	if len(roots) == 1 {
+		d.Tree.Root = roots[0]
+	} else {
+		d.Tree.Root = "some-secret-string"
+		d.Tree.To[d.Tree.Root] = roots
+		d.Nodes[d.Tree.Root] = DiffNode{HasOld: true, HasNew: true}
+	}
+
+	for node, children := range d.Tree.To {
+		d.Tree.To[node] = uniqueInOrder(children)
+	}
+
+	d.setSizes(d.Tree.Root)
+
+	return d
+}
+
+// BiggestChanges returns at most n changes of leaves with largest absolute delta.
+// Unchanged leaves are skipped.
+func (d TreeDiff) BiggestChanges(n int) []DiffNode {
+	var changes []DiffNode
+	for path, c := range d.Nodes {
+		if len(d.Tree.To[path]) == 0 && c.Delta() != 0 {
+			changes = append(changes, c)
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		a, b := math.Abs(changes[i].Delta()), math.Abs(changes[j].Delta())
+		if a != b {
+			return a > b
+		}
+		return changes[i].Path < changes[j].Path
+	})
+
+	if len(changes) > n {
+		changes = changes[:n]
+	}
+	return changes
+}
+
+// addTree adds nodes and edges of tree and returns its roots
+func (d TreeDiff) addTree(t Tree, isNew bool) []string {
+	for path, node := range t.Nodes {
+		if path == "some-secret-string" {
+			continue
+		}
+
+		c := d.Nodes[path]
+		c.Path = path
+		if isNew {
+			c.New, c.HasNew = node.Size, true
+		} else {
+			c.Old, c.HasOld = node.Size, true
+		}
+		d.Nodes[path] = c
+
+		if _, ok := d.Tree.Nodes[path]; !ok {
+			d.Tree.Nodes[path] = Node{Path: path, Name: node.Name}
+		}
+	}
+
+	for path, children := range t.To {
+		if path == "some-secret-string" {
+			continue
+		}
+		d.Tree.To[path] = append(d.Tree.To[path], children...)
+	}
+
+	if t.Root == "some-secret-string" {
+		return t.To[t.Root]
+	}
+	// root of absolute paths is empty path, empty tree has no root
+	_, hasNode := t.Nodes[t.Root]
+	_, hasChildren := t.To[t.Root]
+	if !hasNode && !hasChildren {
+		return nil
+	}
+	return []string{t.Root}
+}
+
+// setSizes sets size of leaves from their new or old size and sums it up to parents
+func (d TreeDiff) setSizes(node string) float64 {
+	n := d.Tree.Nodes[node]
+	c := d.Nodes[node]
+
+	if children := d.Tree.To[node]; len(children) > 0 {
+		n.Size = 0
+		for _, child := range children {
+			n.Size += d.setSizes(child)
+		}
+	} else if c.HasNew {
+		n.Size = c.New
+	} else {
+		n.Size = c.Old
+	}
+
+	if node == d.Tree.Root && node == "some-secret-string" {
+		c.Old, c.New = 0, 0
+		for _, child := range d.Tree.To[node] {
+			c.Old += d.Nodes[child].Old
+			c.New += d.Nodes[child].New
+		}
+		c.Path = node
+		d.Nodes[node] = c
+	}
+
+	n.Path = node
+	n.Heat = c.RelativeChange()
+	n.HasHeat = true
+	d.Tree.Nodes[node] = n
+
+	return n.Size
+}
+
+func uniqueInOrder(vs []string) []string {
+	seen := make(map[string]bool, len(vs))
+	res := make([]string, 0, len(vs))
+	for _, v := range vs {
+		if !seen[v] {
+			seen[v] = true
+			res = append(res, v)
+		}
+	}
+	return res
+}
@@ -0,0 +1,119 @@
+package treemap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffTrees(t *testing.T) {
+	oldTree := Tree{
+		Nodes: map[string]Node{
+			"a":   {Path: "a", Name: "a", Size: 10},
+			"a/b": {Path: "a/b", Name: "b", Size: 4},
+			"a/c": {Path: "a/c", Name: "c", Size: 6},
+		},
+		To:   map[string][]string{"a": {"a/b", "a/c"}},
+		Root: "a",
+	}
+	newTree := Tree{
+		Nodes: map[string]Node{
+			"a":   {Path: "a", Name: "a", Size: 12},
+			"a/b": {Path: "a/b", Name: "b", Size: 8},
+			"a/d": {Path: "a/d", Name: "d", Size: 4},
+		},
+		To:   map[string][]string{"a": {"a/d", "a/b"}},
+		Root: "a",
+	}
+
+	d := DiffTrees(oldTree, newTree)
+
+	if d.Tree.Root != "a" {
+		t.Errorf("root: exp(a) != got(%s)", d.Tree.Root)
+	}
+	if exp, got := []string{"a/d", "a/b", "a/c"}, d.Tree.To["a"]; !reflect.DeepEqual(exp, got) {
+		t.Errorf("children: exp(%v) != got(%v)", exp, got)
+	}
+
+	expNodes := map[string]Node{
+		"a":   {Path: "a", Name: "a", Size: 18, Heat: 2.0 / 12, HasHeat: true},
+		"a/b": {Path: "a/b", Name: "b", Size: 8, Heat: 0.5, HasHeat: true},
+		"a/c": {Path: "a/c", Name: "c", Size: 6, Heat: -1, HasHeat: true},
+		"a/d": {Path: "a/d", Name: "d", Size: 4, Heat: 1, HasHeat: true},
+	}
+	for k, exp := range expNodes {
+		if got := d.Tree.Nodes[k]; got != exp {
+			t.Errorf("%s: exp(%#v) != got(%#v)", k, exp, got)
+		}
+	}
+
+	if c := d.Nodes["a/c"]; !c.IsRemoved() || c.Delta() != -6 {
+		t.Errorf("exp removed node with delta -6, got %#v", c)
+	}
+	if c := d.Nodes["a/d"]; !c.IsAdded() || c.Delta() != 4 {
+		t.Errorf("exp added node with delta 4, got %#v", c)
+	}
+
+	changes := d.BiggestChanges(2)
+	if exp, got := []string{"a/c", "a/b"}, []string{changes[0].Path, changes[1].Path}; !reflect.DeepEqual(exp, got) {
+		t.Errorf("biggest changes: exp(%v) != got(%v)", exp, got)
+	}
+}
+
+func TestDiffTreesDifferentRoots(t *testing.T) {
+	oldTree := Tree{
+		Nodes: map[string]Node{"a": {Path: "a", Name: "a", Size: 1}},
+		Root:  "a",
+	}
+	newTree := Tree{
+		Nodes: map[string]Node{"b": {Path: "b", Name: "b", Size: 3}},
+		Root:  "b",
+	}
+
+	d := DiffTrees(oldTree, newTree)
+
+	if exp, got := []string{"b", "a"}, d.Tree.To[d.Tree.Root]; !reflect.DeepEqual(exp, got) {
+		t.Errorf("roots: exp(%v) != got(%v)", exp, got)
+	}
+	if c := d.Nodes[d.Tree.Root]; c.Old != 1 || c.New != 3 {
+		t.Errorf("wrong root change: %#v", c)
+	}
+	if n := d.Tree.Nodes[d.Tree.Root]; n.Size != 4 {
+		t.Errorf("root size: exp(4) != got(%v)", n.Size)
+	}
+}
+
+func TestDiffTreesAbsolutePaths(t *testing.T) {
+	oldTree := Tree{
+		Nodes: map[string]Node{
+			"":     {Path: "", Size: 1},
+			"/a":   {Path: "/a", Name: "a", Size: 1},
+			"/a/b": {Path: "/a/b", Name: "b", Size: 1},
+		},
+		To:   map[string][]string{"": {"/a"}, "/a": {"/a/b"}},
+		Root: "",
+	}
+	newTree := Tree{
+		Nodes: map[string]Node{
+			"":     {Path: "", Size: 3},
+			"/a":   {Path: "/a", Name: "a", Size: 3},
+			"/a/c": {Path: "/a/c", Name: "c", Size: 3},
+		},
+		To:   map[string][]string{"": {"/a"}, "/a": {"/a/c"}},
+		Root: "",
+	}
+
+	d := DiffTrees(oldTree, newTree)
+
+	if d.Tree.Root != "" {
+		t.Errorf("root: exp() != got(%s)", d.Tree.Root)
+	}
+	if exp, got := []string{"/a/c", "/a/b"}, d.Tree.To["/a"]; !reflect.DeepEqual(exp, got) {
+		t.Errorf("children: exp(%v) != got(%v)", exp, got)
+	}
+	if n := d.Tree.Nodes[""]; n.Size != 4 {
+		t.Errorf("root size: exp(4) != got(%v)", n.Size)
+	}
+	if c := d.Nodes[""]; c.Old != 1 || c.New != 3 {
+		t.Errorf("wrong root change: %#v", c)
+	}
+}
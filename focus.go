@@ -0,0 +1,74 @@
+package treemap
+
+import (
+	"fmt"
+)
+
+// ResolvePath finds node identifier for path in tree.
+// When long paths are collapsed, path can be inside of collapsed chain, then node of whole chain is returned.
+func ResolvePath(t Tree, path string) (string, bool) {
+	if t.Format.HasTrailingSeparator(path) {
+		path = path[:len(path)-len(t.Format.separator())]
+	}
+
+	if _, ok := t.Nodes[path]; ok {
+		return path, true
+	}
+
+	// collapsed node keeps identifier of first node in chain and path of last node in chain
+	for key, node := range t.Nodes {
+		if t.Format.IsPrefix(key, path) && t.Format.IsPrefix(path, node.Path) {
+			return key, true
+		}
+	}
+
+	return "", false
+}
+
+// FocusSubtree re-roots tree at node with path, nodes outside of its subtree are removed.
+// Returns ancestors of new root starting from old root, such as for breadcrumbs.
+// Fake root of multiple roots is not included in ancestors.
+func FocusSubtree(t *Tree, path string) ([]Node, error) {
+	if t == nil {
+		return nil, nil
+	}
+
+	root, ok := ResolvePath(*t, path)
+	if !ok {
+		return nil, fmt.Errorf("node(%s) not found", path)
+	}
+
+	parents := make(map[string]string, len(t.To))
+	for parent, children := range t.To {
+		for _, child := range children {
+			parents[child] = parent
+		}
+	}
+
+	var ancestors []Node
+	for q, ok := parents[root]; ok; q, ok = parents[q] {
+		if q == "some-secret-string" {
+			continue
+		}
+		ancestors = append([]Node{t.Nodes[q]}, ancestors...)
+	}
+
+	nodes := make(map[string]Node)
+	to := make(map[string][]string)
+	que := []string{root}
+	var q string
+	for len(que) > 0 {
+		q, que = que[len(que)-1], que[:len(que)-1]
+		if n, ok := t.Nodes[q]; ok {
+			nodes[q] = n
+		}
+		if children, ok := t.To[q]; ok {
+			to[q] = children
+			que = append(que, children...)
+		}
+	}
+
+	t.Nodes, t.To, t.Root = nodes, to, root
+
+	return ancestors, nil
+}
@@ -0,0 +1,104 @@
+package treemap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFocusSubtree(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		expRoot      string
+		expAncestors []string
+		expNodes     []string
+	}{
+		{
+			name:         "node",
+			path:         "a/b",
+			expRoot:      "a/b",
+			expAncestors: []string{"a"},
+			expNodes:     []string{"a/b", "a/b/c", "a/b/d"},
+		},
+		{
+			name:         "trailing slash",
+			path:         "a/b/",
+			expRoot:      "a/b",
+			expAncestors: []string{"a"},
+			expNodes:     []string{"a/b", "a/b/c", "a/b/d"},
+		},
+		{
+			name:         "inside of collapsed chain",
+			path:         "a/e/f",
+			expRoot:      "a/e",
+			expAncestors: []string{"a"},
+			expNodes:     []string{"a/e", "a/e/f/g/h", "a/e/f/g/i"},
+		},
+		{
+			name:         "end of collapsed chain",
+			path:         "a/e/f/g",
+			expRoot:      "a/e",
+			expAncestors: []string{"a"},
+			expNodes:     []string{"a/e", "a/e/f/g/h", "a/e/f/g/i"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := Tree{
+				Nodes: map[string]Node{
+					"a":         {Path: "a", Name: "a", Size: 7},
+					"a/b":       {Path: "a/b", Name: "b", Size: 3},
+					"a/b/c":     {Path: "a/b/c", Name: "c", Size: 1},
+					"a/b/d":     {Path: "a/b/d", Name: "d", Size: 2},
+					"a/e":       {Path: "a/e/f/g", Name: "e/f/g", Size: 4},
+					"a/e/f/g/h": {Path: "a/e/f/g/h", Name: "h", Size: 1},
+					"a/e/f/g/i": {Path: "a/e/f/g/i", Name: "i", Size: 3},
+				},
+				To: map[string][]string{
+					"a":   {"a/b", "a/e"},
+					"a/b": {"a/b/c", "a/b/d"},
+					"a/e": {"a/e/f/g/h", "a/e/f/g/i"},
+				},
+				Root: "a",
+			}
+
+			ancestors, err := FocusSubtree(&tree, tc.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tree.Root != tc.expRoot {
+				t.Errorf("root: exp(%s) != got(%s)", tc.expRoot, tree.Root)
+			}
+
+			var gotAncestors []string
+			for _, n := range ancestors {
+				gotAncestors = append(gotAncestors, n.Path)
+			}
+			if !reflect.DeepEqual(tc.expAncestors, gotAncestors) {
+				t.Errorf("ancestors: exp(%v) != got(%v)", tc.expAncestors, gotAncestors)
+			}
+
+			if len(tree.Nodes) != len(tc.expNodes) {
+				t.Errorf("nodes: exp(%v) != got(%v)", tc.expNodes, tree.Nodes)
+			}
+			for _, n := range tc.expNodes {
+				if _, ok := tree.Nodes[n]; !ok {
+					t.Errorf("node(%s) is missing", n)
+				}
+			}
+		})
+	}
+}
+
+func TestFocusSubtreeNotFound(t *testing.T) {
+	tree := Tree{
+		Nodes: map[string]Node{"a": {Path: "a", Name: "a"}},
+		Root:  "a",
+	}
+
+	if _, err := FocusSubtree(&tree, "a/b"); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
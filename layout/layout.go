@@ -0,0 +1,64 @@
+package layout
+
+// Layout partitions box into boxes with given areas.
+// Returns boxes in same order as areas.
+// Zero areas will have zero-value box.
+type Layout interface {
+	Layout(box Box, areas []float64) []Box
+}
+
+// LayoutFunc is adapter to use ordinary functions as Layout.
+type LayoutFunc func(box Box, areas []float64) []Box
+
+func (f LayoutFunc) Layout(box Box, areas []float64) []Box { return f(box, areas) }
+
+// GetLayout returns layout by name.
+// Squarified layout has best aspect ratios, but sorts areas.
+// Slice-and-dice, strip and pivot layouts preserve order of areas.
+func GetLayout(name string) (Layout, bool) {
+	switch name {
+	case "squarify":
+		return LayoutFunc(Squarify), true
+	case "slice-dice":
+		return LayoutFunc(SliceAndDice), true
+	case "strip":
+		return LayoutFunc(Strip), true
+	case "pivot":
+		return LayoutFunc(PivotByMiddle), true
+	default:
+		return nil, false
+	}
+}
+
+// positiveAreas returns indexes of positive areas and these areas normalized to area of box.
+func positiveAreas(box Box, areas []float64) (idx []int, normalized []float64) {
+	idx = make([]int, 0, len(areas))
+	clean := make([]float64, 0, len(areas))
+	for i, s := range areas {
+		if s > 0 {
+			idx = append(idx, i)
+			clean = append(clean, s)
+		}
+	}
+	if len(clean) == 0 {
+		return nil, nil
+	}
+	return idx, normalizeAreas(clean, box.W*box.H)
+}
+
+// restoreOrder places boxes of positive areas into result with same order as all areas.
+func restoreOrder(n int, idx []int, boxes []Box) []Box {
+	res := make([]Box, n)
+	for i, b := range boxes {
+		res[idx[i]] = b
+	}
+	return res
+}
+
+func sum(areas []float64) float64 {
+	var s float64
+	for _, v := range areas {
+		s += v
+	}
+	return s
+}
@@ -0,0 +1,68 @@
+package layout
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGetLayout(t *testing.T) {
+	for _, name := range []string{"squarify", "slice-dice", "strip", "pivot"} {
+		if l, ok := GetLayout(name); !ok || l == nil {
+			t.Errorf("%s: layout not found", name)
+		}
+	}
+	if _, ok := GetLayout("unknown"); ok {
+		t.Error("expected unknown layout to be not found")
+	}
+}
+
+func TestLayoutsAreasAndBounds(t *testing.T) {
+	tests := []struct {
+		name  string
+		box   Box
+		areas []float64
+	}{
+		{name: "paper example", box: Box{W: 6, H: 4}, areas: []float64{6, 6, 4, 3, 2, 2, 1}},
+		{name: "tall box", box: Box{X: 1, Y: 2, W: 4, H: 12}, areas: []float64{1, 5, 2, 8, 3}},
+		{name: "need to normalize", box: Box{W: 12, H: 3}, areas: []float64{2, 2, 2}},
+		{name: "with zero", box: Box{W: 12, H: 3}, areas: []float64{1, 0, 3}},
+		{name: "single", box: Box{W: 12, H: 3}, areas: []float64{5}},
+	}
+	for _, name := range []string{"squarify", "slice-dice", "strip", "pivot"} {
+		l, _ := GetLayout(name)
+		for _, tc := range tests {
+			t.Run(name+" "+tc.name, func(t *testing.T) {
+				boxes := l.Layout(tc.box, tc.areas)
+				if len(boxes) != len(tc.areas) {
+					t.Fatalf("exp(%d) boxes != got(%d)", len(tc.areas), len(boxes))
+				}
+
+				total := sum(tc.areas)
+				for i, b := range boxes {
+					if tc.areas[i] == 0 {
+						if b != NilBox {
+							t.Errorf("box(%d) of zero area is not nil: %#v", i, b)
+						}
+						continue
+					}
+					expArea := tc.box.W * tc.box.H * tc.areas[i] / total
+					if math.Abs(b.W*b.H-expArea) > 0.0001 {
+						t.Errorf("box(%d: %#v) area exp(%f) != got(%f)", i, b, expArea, b.W*b.H)
+					}
+					if b.X < tc.box.X || b.Y < tc.box.Y || (b.X+b.W) > (tc.box.X+tc.box.W+0.0001) || (b.Y+b.H) > (tc.box.Y+tc.box.H+0.0001) {
+						t.Errorf("box(%d: %#v) overflows", i, b)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestLayoutsEmpty(t *testing.T) {
+	for _, name := range []string{"squarify", "slice-dice", "strip", "pivot"} {
+		l, _ := GetLayout(name)
+		if boxes := l.Layout(Box{W: 6, H: 4}, []float64{0, 0}); !eqSliceBox([]Box{{}, {}}, boxes) {
+			t.Errorf("%s: exp zero boxes, got(%#v)", name, boxes)
+		}
+	}
+}
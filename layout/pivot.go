@@ -0,0 +1,77 @@
+package layout
+
+import "math"
+
+// PivotByMiddle partitions box with ordered treemap algorithm, which keeps order of areas from top-left to bottom-right.
+// Middle area is pivot, areas before it take region on one side, and areas after it are split in two regions next to pivot
+// such that pivot is as square as possible.
+// As described in "Ordered Treemap Layouts", Ben Shneiderman and Martin Wattenberg, 2001.
+func PivotByMiddle(box Box, areas []float64) []Box {
+	idx, clean := positiveAreas(box, areas)
+	if len(clean) == 0 || box.W <= 0 || box.H <= 0 {
+		return make([]Box, len(areas))
+	}
+
+	boxes := make([]Box, len(clean))
+	pivotByMiddle(box, clean, boxes)
+
+	cutoffOverflows(box, boxes)
+	return restoreOrder(len(areas), idx, boxes)
+}
+
+// pivotByMiddle lays out areas that add up to area of box into boxes
+func pivotByMiddle(box Box, areas []float64, boxes []Box) {
+	switch len(areas) {
+	case 0:
+		return
+	case 1:
+		boxes[0] = box
+		return
+	}
+
+	// regions are computed for wide box, tall box is transposed
+	tall := box.H > box.W
+	if tall {
+		box = transpose(box)
+	}
+
+	p := len(areas) / 2
+	total := sum(areas)
+	a1, ap, rest := sum(areas[:p]), areas[p], areas[p+1:]
+
+	// region before pivot
+	w1 := box.W * a1 / total
+	r1 := Box{X: box.X, Y: box.Y, W: w1, H: box.H}
+
+	// split rest of areas after pivot into ones below pivot and ones to the right of it
+	x, w := box.X+w1, box.W-w1
+	bestK, bestRatio := 0, math.Inf(1)
+	for k := 0; k <= len(rest); k++ {
+		a2 := sum(rest[:k])
+		wp := w * (ap + a2) / (total - a1)
+		hp := box.H * ap / (ap + a2)
+		if r := aspectRatio(wp, hp); r < bestRatio {
+			bestK, bestRatio = k, r
+		}
+	}
+
+	a2 := sum(rest[:bestK])
+	wp := w * (ap + a2) / (total - a1)
+	hp := box.H * ap / (ap + a2)
+	rp := Box{X: x, Y: box.Y, W: wp, H: hp}
+	r2 := Box{X: x, Y: box.Y + hp, W: wp, H: box.H - hp}
+	r3 := Box{X: x + wp, Y: box.Y, W: w - wp, H: box.H}
+
+	if tall {
+		r1, rp, r2, r3 = transpose(r1), transpose(rp), transpose(r2), transpose(r3)
+	}
+
+	pivotByMiddle(r1, areas[:p], boxes[:p])
+	boxes[p] = rp
+	pivotByMiddle(r2, rest[:bestK], boxes[p+1:p+1+bestK])
+	pivotByMiddle(r3, rest[bestK:], boxes[p+1+bestK:])
+}
+
+func transpose(b Box) Box {
+	return Box{X: b.Y, Y: b.X, W: b.H, H: b.W}
+}
@@ -0,0 +1,49 @@
+package layout
+
+import "testing"
+
+func TestPivotByMiddle(t *testing.T) {
+	tests := []struct {
+		name     string
+		box      Box
+		areas    []float64
+		expBoxes []Box
+	}{
+		{
+			name:  "when two areas, then first is before pivot",
+			box:   Box{W: 4, H: 2},
+			areas: []float64{1, 1},
+			expBoxes: []Box{
+				{X: 0, Y: 0, W: 2, H: 2},
+				{X: 2, Y: 0, W: 2, H: 2},
+			},
+		},
+		{
+			name:  "when areas after pivot, then pivot is made square",
+			box:   Box{W: 4, H: 2},
+			areas: []float64{4, 2, 1, 1},
+			expBoxes: []Box{
+				{X: 0, Y: 0, W: 2, H: 2},
+				{X: 2, Y: 0, W: 1, H: 2},
+				{X: 3, Y: 0, W: 1, H: 1},
+				{X: 3, Y: 1, W: 1, H: 1},
+			},
+		},
+		{
+			name:  "when tall box, then region before pivot is on top",
+			box:   Box{W: 2, H: 4},
+			areas: []float64{1, 1},
+			expBoxes: []Box{
+				{X: 0, Y: 0, W: 2, H: 2},
+				{X: 0, Y: 2, W: 2, H: 2},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if boxes := PivotByMiddle(tc.box, tc.areas); !eqSliceBox(tc.expBoxes, boxes) {
+				t.Errorf("wrong boxes: exp(%#v) != got(%#v)", tc.expBoxes, boxes)
+			}
+		})
+	}
+}
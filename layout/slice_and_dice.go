@@ -0,0 +1,30 @@
+package layout
+
+// SliceAndDice partitions box into parallel slices in same order as areas.
+// Slices are cut along longer side of box, so that nested boxes alternate orientation.
+// This is the original treemap layout, described in "Tree visualization with tree-maps: 2-d space-filling approach", Ben Shneiderman, 1992.
+func SliceAndDice(box Box, areas []float64) []Box {
+	idx, clean := positiveAreas(box, areas)
+	if len(clean) == 0 || box.W <= 0 || box.H <= 0 {
+		return make([]Box, len(areas))
+	}
+
+	total := sum(clean)
+	boxes := make([]Box, len(clean))
+
+	offset := 0.0
+	for i, s := range clean {
+		if box.W >= box.H {
+			w := box.W * s / total
+			boxes[i] = Box{X: box.X + offset, Y: box.Y, W: w, H: box.H}
+			offset += w
+		} else {
+			h := box.H * s / total
+			boxes[i] = Box{X: box.X, Y: box.Y + offset, W: box.W, H: h}
+			offset += h
+		}
+	}
+
+	cutoffOverflows(box, boxes)
+	return restoreOrder(len(areas), idx, boxes)
+}
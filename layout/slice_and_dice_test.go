@@ -0,0 +1,39 @@
+package layout
+
+import "testing"
+
+func TestSliceAndDice(t *testing.T) {
+	tests := []struct {
+		name     string
+		box      Box
+		areas    []float64
+		expBoxes []Box
+	}{
+		{
+			name:  "when wide box, then vertical slices in same order",
+			box:   Box{W: 6, H: 4},
+			areas: []float64{1, 0, 2},
+			expBoxes: []Box{
+				{X: 0, Y: 0, W: 2, H: 4},
+				{},
+				{X: 2, Y: 0, W: 4, H: 4},
+			},
+		},
+		{
+			name:  "when tall box, then horizontal slices in same order",
+			box:   Box{X: 1, Y: 1, W: 2, H: 6},
+			areas: []float64{2, 1},
+			expBoxes: []Box{
+				{X: 1, Y: 1, W: 2, H: 4},
+				{X: 1, Y: 5, W: 2, H: 2},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if boxes := SliceAndDice(tc.box, tc.areas); !eqSliceBox(tc.expBoxes, boxes) {
+				t.Errorf("wrong boxes: exp(%#v) != got(%#v)", tc.expBoxes, boxes)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package layout
+
+import "math"
+
+// Strip partitions box into horizontal strips, filling strips from left to right and from top to bottom in same order as areas.
+// New strip is started when adding area to current strip does not improve average aspect ratio of strip.
+// As described in "Ordered and Quantum Treemaps: Making Effective Use of 2D Space to Display Hierarchies", Benjamin B. Bederson, Ben Shneiderman, and Martin Wattenberg, 2002.
+func Strip(box Box, areas []float64) []Box {
+	idx, clean := positiveAreas(box, areas)
+	if len(clean) == 0 || box.W <= 0 || box.H <= 0 {
+		return make([]Box, len(areas))
+	}
+
+	boxes := make([]Box, 0, len(clean))
+	y := box.Y
+
+	var strip []float64
+	for _, s := range clean {
+		if len(strip) > 0 && averageAspectRatio(append(strip, s), box.W) > averageAspectRatio(strip, box.W) {
+			boxes = append(boxes, stripBoxes(strip, box.X, y, box.W)...)
+			y += sum(strip) / box.W
+			strip = nil
+		}
+		strip = append(strip, s)
+	}
+	boxes = append(boxes, stripBoxes(strip, box.X, y, box.W)...)
+
+	cutoffOverflows(box, boxes)
+	return restoreOrder(len(areas), idx, boxes)
+}
+
+// stripBoxes makes boxes of areas in horizontal strip of width w
+func stripBoxes(strip []float64, x, y, w float64) []Box {
+	h := sum(strip) / w
+	boxes := make([]Box, len(strip))
+	for i, s := range strip {
+		boxes[i] = Box{X: x, Y: y, W: s / h, H: h}
+		x += s / h
+	}
+	return boxes
+}
+
+// averageAspectRatio of areas in horizontal strip of width w
+func averageAspectRatio(strip []float64, w float64) float64 {
+	h := sum(strip) / w
+	var total float64
+	for _, s := range strip {
+		total += aspectRatio(s/h, h)
+	}
+	return total / float64(len(strip))
+}
+
+// aspectRatio is at least one, and one for square
+func aspectRatio(w, h float64) float64 {
+	return math.Max(w/h, h/w)
+}
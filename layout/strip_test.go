@@ -0,0 +1,41 @@
+package layout
+
+import "testing"
+
+func TestStrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		box      Box
+		areas    []float64
+		expBoxes []Box
+	}{
+		{
+			name:  "when equal areas, then fills strips in same order",
+			box:   Box{W: 4, H: 4},
+			areas: []float64{1, 1, 1, 1},
+			expBoxes: []Box{
+				{X: 0, Y: 0, W: 2, H: 2},
+				{X: 2, Y: 0, W: 2, H: 2},
+				{X: 0, Y: 2, W: 2, H: 2},
+				{X: 2, Y: 2, W: 2, H: 2},
+			},
+		},
+		{
+			name:  "when adding area makes strip worse, then starts new strip",
+			box:   Box{W: 4, H: 4},
+			areas: []float64{8, 4, 4},
+			expBoxes: []Box{
+				{X: 0, Y: 0, W: 2.6666666666666665, H: 3},
+				{X: 2.6666666666666665, Y: 0, W: 1.3333333333333333, H: 3},
+				{X: 0, Y: 3, W: 4, H: 1},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if boxes := Strip(tc.box, tc.areas); !eqSliceBox(tc.expBoxes, boxes) {
+				t.Errorf("wrong boxes: exp(%#v) != got(%#v)", tc.expBoxes, boxes)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package treemap
+
+import (
+	"fmt"
+	"sort"
+)
+
+// OtherAggregator merges small children of each parent into single "Other (k items)" node,
+// so that their area is visible instead of boxes too small to render.
+// Expects sizes to be imputed. Merged children and their subtrees are removed from tree.
+type OtherAggregator struct {
+	TopN     int      // keeps at most this many largest children, no limit when zero
+	MinShare float64  // keeps children with at least this share of sum of sizes of their siblings and themselves, such as 0.01, no limit when zero
+	Progress Progress // no progress is reported when not set
+}
+
+// Aggregate merges small children in whole tree.
+func (s OtherAggregator) Aggregate(t *Tree) {
+	if t == nil {
+		return
+	}
+
+	bar := ProgressOrNop(s.Progress)
+	bar.Start("Aggregating small nodes", int64(len(t.Nodes)))
+
+	s.aggregateNode(t, t.Root, bar)
+
+	bar.Finish()
+}
+
+func (s OtherAggregator) aggregateNode(t *Tree, node string, bar Progress) {
+	bar.Add(1)
+
+	keep, other := s.split(t, t.To[node])
+
+	// single child is shown as is, it takes same space as other node
+	if len(other) > 1 {
+		parent, hasParent := t.Format.Parent(other[0])
+
+		var n Node
+		var heatSize float64
+		for _, child := range other {
+			c := t.Nodes[child]
+			n.Size += c.Size
+			if c.HasHeat {
+				n.Heat += c.Heat * c.Size
+				heatSize += c.Size
+			}
+			deleteSubtree(t, child)
+		}
+		if heatSize > 0 {
+			n.Heat /= heatSize
+			n.HasHeat = true
+		}
+
+		// other node is sibling of merged children, numbered when kept sibling has same name
+		var name, path string
+		for i := 1; i == 1 || hasNode(t, path); i++ {
+			name = fmt.Sprintf("Other (%d items)", len(other))
+			if i > 1 {
+				name = fmt.Sprintf("Other (%d items) #%d", len(other), i)
+			}
+			name = t.Format.EscapePart(name)
+			path = name
+			if hasParent {
+				path = t.Format.Join(parent, name)
+			}
+		}
+		n.Path, n.Name = path, name
+
+		t.Nodes[path] = n
+		t.To[node] = append(keep, path)
+	}
+
+	for _, child := range t.To[node] {
+		s.aggregateNode(t, child, bar)
+	}
+}
+
+// split children into kept and merged, both in same order as children
+func (s OtherAggregator) split(t *Tree, children []string) (keep, other []string) {
+	var total float64
+	for _, child := range children {
+		total += t.Nodes[child].Size
+	}
+
+	bySize := make([]string, len(children))
+	copy(bySize, children)
+	sort.SliceStable(bySize, func(i, j int) bool { return t.Nodes[bySize[i]].Size > t.Nodes[bySize[j]].Size })
+
+	rank := make(map[string]int, len(bySize))
+	for i, child := range bySize {
+		rank[child] = i
+	}
+
+	for _, child := range children {
+		isTop := s.TopN <= 0 || rank[child] < s.TopN
+		isLarge := s.MinShare <= 0 || total <= 0 || t.Nodes[child].Size/total >= s.MinShare
+		if isTop && isLarge {
+			keep = append(keep, child)
+		} else {
+			other = append(other, child)
+		}
+	}
+
+	return keep, other
+}
+
+func hasNode(t *Tree, node string) bool {
+	_, ok := t.Nodes[node]
+	_, hasChildren := t.To[node]
+	return ok || hasChildren
+}
+
+func deleteSubtree(t *Tree, node string) {
+	for _, child := range t.To[node] {
+		deleteSubtree(t, child)
+	}
+	delete(t.Nodes, node)
+	delete(t.To, node)
+}
@@ -0,0 +1,117 @@
+package treemap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOtherAggregator(t *testing.T) {
+	tests := []struct {
+		name       string
+		aggregator OtherAggregator
+		expTo      []string
+		expOther   Node
+	}{
+		{
+			name:       "top n",
+			aggregator: OtherAggregator{TopN: 2},
+			expTo:      []string{"a/b", "a/c", "a/Other (3 items)"},
+			expOther:   Node{Path: "a/Other (3 items)", Name: "Other (3 items)", Size: 6, Heat: 1, HasHeat: true},
+		},
+		{
+			name:       "min share",
+			aggregator: OtherAggregator{MinShare: 0.1},
+			expTo:      []string{"a/b", "a/c", "a/d", "a/Other (2 items)"},
+			expOther:   Node{Path: "a/Other (2 items)", Name: "Other (2 items)", Size: 2, Heat: 1, HasHeat: true},
+		},
+		{
+			name:       "single small child is kept",
+			aggregator: OtherAggregator{TopN: 4},
+			expTo:      []string{"a/b", "a/c", "a/d", "a/e", "a/f"},
+		},
+		{
+			name:       "no limits",
+			aggregator: OtherAggregator{},
+			expTo:      []string{"a/b", "a/c", "a/d", "a/e", "a/f"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := Tree{
+				Nodes: map[string]Node{
+					"a":     {Path: "a", Name: "a", Size: 20},
+					"a/b":   {Path: "a/b", Name: "b", Size: 10},
+					"a/c":   {Path: "a/c", Name: "c", Size: 4, Heat: 2, HasHeat: true},
+					"a/d":   {Path: "a/d", Name: "d", Size: 4},
+					"a/e":   {Path: "a/e", Name: "e", Size: 1, Heat: 1, HasHeat: true},
+					"a/f":   {Path: "a/f", Name: "f", Size: 1},
+					"a/f/g": {Path: "a/f/g", Name: "g", Size: 1},
+				},
+				To: map[string][]string{
+					"a":   {"a/b", "a/c", "a/d", "a/e", "a/f"},
+					"a/f": {"a/f/g"},
+				},
+				Root: "a",
+			}
+
+			tc.aggregator.Aggregate(&tree)
+
+			if got := tree.To["a"]; !reflect.DeepEqual(tc.expTo, got) {
+				t.Errorf("children: exp(%v) != got(%v)", tc.expTo, got)
+			}
+			if tc.expOther.Path != "" {
+				if got := tree.Nodes[tc.expOther.Path]; got != tc.expOther {
+					t.Errorf("other: exp(%#v) != got(%#v)", tc.expOther, got)
+				}
+				if _, ok := tree.Nodes["a/f/g"]; ok {
+					t.Error("descendants of merged node are not removed")
+				}
+			}
+		})
+	}
+}
+
+func TestOtherAggregatorMultipleRoots(t *testing.T) {
+	tree := Tree{
+		Nodes: map[string]Node{
+			"some-secret-string": {Path: "some-secret-string", Size: 3},
+			"a":                  {Path: "a", Name: "a", Size: 1},
+			"b":                  {Path: "b", Name: "b", Size: 1},
+			"c":                  {Path: "c", Name: "c", Size: 1},
+		},
+		To:   map[string][]string{"some-secret-string": {"a", "b", "c"}},
+		Root: "some-secret-string",
+	}
+
+	OtherAggregator{TopN: 1}.Aggregate(&tree)
+
+	if exp, got := []string{"a", "Other (2 items)"}, tree.To[tree.Root]; !reflect.DeepEqual(exp, got) {
+		t.Errorf("exp(%v) != got(%v)", exp, got)
+	}
+}
+
+func TestOtherAggregatorNameOfSibling(t *testing.T) {
+	tree := Tree{
+		Nodes: map[string]Node{
+			"a":                 {Path: "a", Name: "a", Size: 12},
+			"a/Other (2 items)": {Path: "a/Other (2 items)", Name: "Other (2 items)", Size: 10},
+			"a/b":               {Path: "a/b", Name: "b", Size: 1},
+			"a/c":               {Path: "a/c", Name: "c", Size: 1},
+		},
+		To:   map[string][]string{"a": {"a/Other (2 items)", "a/b", "a/c"}},
+		Root: "a",
+	}
+
+	OtherAggregator{TopN: 1}.Aggregate(&tree)
+
+	if exp, got := []string{"a/Other (2 items)", "a/Other (2 items) #2"}, tree.To["a"]; !reflect.DeepEqual(exp, got) {
+		t.Errorf("exp(%v) != got(%v)", exp, got)
+	}
+	if exp, got := 10.0, tree.Nodes["a/Other (2 items)"].Size; exp != got {
+		t.Errorf("sibling: exp(%v) != got(%v)", exp, got)
+	}
+	if exp, got := 2.0, tree.Nodes["a/Other (2 items) #2"].Size; exp != got {
+		t.Errorf("other: exp(%v) != got(%v)", exp, got)
+	}
+}
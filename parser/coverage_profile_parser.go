@@ -0,0 +1,137 @@
+package parser
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/MazenAlkhatib/treemap"
+)
+
+// CoverageProfileParser parses profile of go test -coverprofile into a tree structure of files,
+// sized by number of statements and with heat of share of covered statements, from 0 to 1.
+// Heat of directories is share of covered statements in them when sizes are imputed by treemap.SumSizeImputer.
+// Blocks repeated in merged profiles are counted once, covered when covered in any.
+type CoverageProfileParser struct {
+	Progress treemap.Progress // no progress is reported when not set
+}
+
+// coverageBlock is position of block in file, such as "github.com/foo/bar/x.go:10.2,12.3"
+type coverageBlock string
+
+type coverageFile struct {
+	statements, covered int
+}
+
+// ParseReader parses coverage profile from a reader into a tree structure
+func (s *CoverageProfileParser) ParseReader(reader io.Reader) (*treemap.Tree, error) {
+	return s.ParseReaderContext(context.Background(), reader)
+}
+
+// ParseReaderContext is same as ParseReader, but stops with context error when context is done.
+func (s *CoverageProfileParser) ParseReaderContext(ctx context.Context, reader io.Reader) (*treemap.Tree, error) {
+	bar := treemap.ProgressOrNop(s.Progress)
+	bar.Start("Parsing coverage profile", -1)
+	defer bar.Finish()
+
+	var files []string
+	stats := make(map[string]*coverageFile)
+	blocks := make(map[coverageBlock]bool)
+
+	r := bufio.NewScanner(reader)
+	for line := 1; r.Scan(); line++ {
+		if line%checkContextEvery == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		text := strings.TrimSpace(r.Text())
+		// merged profiles have mode line of each profile
+		if text == "" || strings.HasPrefix(text, "mode:") {
+			continue
+		}
+
+		// file path can contain spaces and colons, numbers are last
+		fields := strings.Fields(text)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("line(%d) is not block of coverage profile: %s", line, text)
+		}
+		block := coverageBlock(strings.Join(fields[:len(fields)-2], " "))
+		i := strings.LastIndexByte(string(block), ':')
+		if i <= 0 {
+			return nil, fmt.Errorf("line(%d) has no file: %s", line, text)
+		}
+		file := string(block[:i])
+
+		statements, err := strconv.Atoi(fields[len(fields)-2])
+		if err != nil {
+			return nil, fmt.Errorf("statements(%s) in line(%d) is not integer: %w", fields[len(fields)-2], line, err)
+		}
+		count, err := strconv.Atoi(fields[len(fields)-1])
+		if err != nil {
+			return nil, fmt.Errorf("count(%s) in line(%d) is not integer: %w", fields[len(fields)-1], line, err)
+		}
+
+		stat, ok := stats[file]
+		if !ok {
+			stat = &coverageFile{}
+			stats[file] = stat
+			files = append(files, file)
+		}
+
+		covered, seen := blocks[block]
+		if !seen {
+			stat.statements += statements
+		}
+		if count > 0 && !covered {
+			stat.covered += statements
+			blocks[block] = true
+		} else if !seen {
+			blocks[block] = false
+		}
+
+		bar.Add(1)
+	}
+	if err := r.Err(); err != nil {
+		return nil, fmt.Errorf("error reading coverage profile: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no roots, empty input")
+	}
+
+	b := newTreeBuilder()
+	b.setNames = true
+	for _, file := range files {
+		stat := stats[file]
+		node := treemap.Node{Path: file, Size: float64(stat.statements)}
+		if stat.statements > 0 {
+			node.Heat = float64(stat.covered) / float64(stat.statements)
+			node.HasHeat = true
+		}
+		b.add(node)
+	}
+
+	return b.build()
+}
+
+// ParseFile parses coverage profile file into a tree structure
+func (s *CoverageProfileParser) ParseFile(filepath string) (*treemap.Tree, error) {
+	return s.ParseFileContext(context.Background(), filepath)
+}
+
+// ParseFileContext is same as ParseFile, but stops with context error when context is done.
+func (s *CoverageProfileParser) ParseFileContext(ctx context.Context, filepath string) (*treemap.Tree, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open file: %w", err)
+	}
+	defer file.Close()
+
+	return s.ParseReaderContext(ctx, file)
+}
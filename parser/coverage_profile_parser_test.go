@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MazenAlkhatib/treemap"
+)
+
+func TestCoverageProfileParser(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		csv    string
+		expErr string
+	}{
+		{
+			name: "when profile, then files sized by statements with share of covered",
+			in: `mode: set
+github.com/foo/bar/a.go:10.2,12.3 2 1
+github.com/foo/bar/a.go:12.3,14.3 3 0
+github.com/foo/bar/baz/b.go:1.1,2.2 4 1
+`,
+			csv: "github.com/foo/bar/a.go,5,0.4\ngithub.com/foo/bar/baz/b.go,4,1\n",
+		},
+		{
+			name: "when merged profiles, then blocks are counted once",
+			in: `mode: count
+a/x.go:1.1,2.2 2 0
+a/y.go:1.1,2.2 1 0
+mode: count
+a/x.go:1.1,2.2 2 3
+a/y.go:1.1,2.2 1 0
+`,
+			csv: "a/x.go,2,1\na/y.go,1,0\n",
+		},
+		{
+			name:   "when not profile, then error",
+			in:     "mode: set\na/b,1\n",
+			expErr: "line(2) is not block of coverage profile",
+		},
+		{
+			name:   "when count is not integer, then error",
+			in:     "a/x.go:1.1,2.2 2 x\n",
+			expErr: "count(x) in line(1) is not integer",
+		},
+		{
+			name:   "when empty, then error",
+			in:     "mode: set\n",
+			expErr: "no roots",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tree, err := (&CoverageProfileParser{}).ParseReader(strings.NewReader(tc.in))
+			assertError(t, err, tc.expErr)
+			if err != nil {
+				return
+			}
+
+			expTree, err := (&CSVTreeParser{}).ParseReader(strings.NewReader(tc.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !eqTree(*expTree, *tree) {
+				t.Errorf("tree: exp(%#v) != got(%#v)", expTree, tree)
+			}
+		})
+	}
+}
+
+func TestCoverageProfileParserImputedHeat(t *testing.T) {
+	in := "mode: set\na/x.go:1.1,2.2 3 1\na/x.go:2.2,3.3 1 0\na/b/y.go:1.1,2.2 4 0\n"
+
+	tree, err := (&CoverageProfileParser{}).ParseReader(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	treemap.SumSizeImputer{EmptyLeafSize: 1}.ImputeSize(*tree)
+
+	// 3 of 8 statements are covered
+	if exp, got := 0.375, tree.Nodes["a"].Heat; exp != got {
+		t.Errorf("exp(%v) != got(%v)", exp, got)
+	}
+	if exp, got := 8.0, tree.Nodes["a"].Size; exp != got {
+		t.Errorf("size: exp(%v) != got(%v)", exp, got)
+	}
+}
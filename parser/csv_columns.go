@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CSVColumns maps columns of records to fields of nodes, by name in header or by zero-based index.
+// When no column is mapped, then path, size and optional heat are first three columns.
+// Otherwise, columns that are not mapped are not read, and empty size and heat are treated as missing.
+// Header is required when any column is mapped by name.
+type CSVColumns struct {
+	Path  string // required when any column is mapped
+	Size  string
+	Heat  string
+	Label string // name of node instead of last part of path, not supported by compact tree
+}
+
+// columnIndexes are indexes of columns in records, -1 when column is not read
+type columnIndexes struct {
+	path, size, heat, label int
+
+	// skipEmpty treats empty cells as missing
+	skipEmpty bool
+}
+
+// defaultColumns are path, size and heat, as in header-less CSV
+var defaultColumns = columnIndexes{path: 0, size: 1, heat: 2, label: -1}
+
+func (c CSVColumns) isZero() bool { return c == CSVColumns{} }
+
+// hasNames is true when any column is mapped by name, so that header is required
+func (c CSVColumns) hasNames() bool {
+	for _, v := range []string{c.Path, c.Size, c.Heat, c.Label} {
+		if _, err := strconv.Atoi(v); v != "" && err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// indexes finds columns in header, header is not used when columns are mapped by index only
+func (c CSVColumns) indexes(header []string) (columnIndexes, error) {
+	if c.isZero() {
+		return defaultColumns, nil
+	}
+	if c.Path == "" {
+		return columnIndexes{}, errors.New("path column is not set")
+	}
+
+	cols := columnIndexes{skipEmpty: true}
+	for _, col := range []struct {
+		name  string
+		index *int
+	}{
+		{name: c.Path, index: &cols.path},
+		{name: c.Size, index: &cols.size},
+		{name: c.Heat, index: &cols.heat},
+		{name: c.Label, index: &cols.label},
+	} {
+		i, err := columnIndex(col.name, header)
+		if err != nil {
+			return columnIndexes{}, err
+		}
+		*col.index = i
+	}
+
+	return cols, nil
+}
+
+// columnIndex is index of column by its name or index, -1 for empty column
+func columnIndex(column string, header []string) (int, error) {
+	if column == "" {
+		return -1, nil
+	}
+
+	if i, err := strconv.Atoi(column); err == nil {
+		if i < 0 {
+			return 0, fmt.Errorf("column(%d) is negative", i)
+		}
+		return i, nil
+	}
+
+	for i, name := range header {
+		// byte order mark is common in exports from spreadsheets
+		if strings.TrimPrefix(name, "\ufeff") == column {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("column(%s) not found in header", column)
+}
+
+// cell is value of column in record, false when column is not read or missing
+func (c columnIndexes) cell(record []string, i int) (string, bool) {
+	if i < 0 || i >= len(record) {
+		return "", false
+	}
+	if c.skipEmpty && record[i] == "" {
+		return "", false
+	}
+	return record[i], true
+}
@@ -1,139 +1,347 @@
 package parser
 
 import (
+	"context"
 	"encoding/csv"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/MazenAlkhatib/treemap"
-	"github.com/schollz/progressbar/v3"
 )
 
-// CSVTreeParser handles parsing of CSV data into a tree structure
+// CSVTreeParser handles parsing of CSV data into a tree structure.
+// Expected columns are path, size and optional heat, unless Columns are mapped.
 type CSVTreeParser struct {
-	Comma rune
+	Comma    rune // such as '\t' for TSV, ',' when not set
+	Columns  CSVColumns
+	Header   bool               // first record is skipped as header, header is always read when columns are mapped by name
+	Format   treemap.PathFormat // how paths are split into parts, "/" without escaping when not set
+	Progress treemap.Progress   // no progress is reported when not set
 }
 
+// checkContextEvery is how many records are read between checks of context for cancellation
+const checkContextEvery = 1024
+
 // ParseReader parses CSV data from a reader into a tree structure
 func (s *CSVTreeParser) ParseReader(reader io.Reader) (*treemap.Tree, error) {
-	tree := &treemap.Tree{
-		Nodes: make(map[string]treemap.Node),
-		To:    make(map[string][]string),
+	return s.ParseReaderContext(context.Background(), reader)
+}
+
+// ParseReaderContext is same as ParseReader, but stops with context error when context is done.
+func (s *CSVTreeParser) ParseReaderContext(ctx context.Context, reader io.Reader) (*treemap.Tree, error) {
+	b := newTreeBuilder()
+	b.setNames = true
+	b.tree.Format = s.Format
+
+	if err := s.readNodes(ctx, reader, b.add); err != nil {
+		return nil, err
 	}
 
-	// for finding roots
-	hasParent := make(map[string]bool)
-	// for tracking unique children
-	uniqueChildren := make(map[string]map[string]bool)
+	return b.build()
+}
 
-	r := csv.NewReader(reader)
-	if s.Comma != 0 {
-		r.Comma = s.Comma
+// ParseReaderCompact parses CSV data from a reader into a compact tree structure, for large inputs
+func (s *CSVTreeParser) ParseReaderCompact(reader io.Reader) (*treemap.CompactTree, error) {
+	return s.ParseReaderCompactContext(context.Background(), reader)
+}
+
+// ParseReaderCompactContext is same as ParseReaderCompact, but stops with context error when context is done.
+func (s *CSVTreeParser) ParseReaderCompactContext(ctx context.Context, reader io.Reader) (*treemap.CompactTree, error) {
+	b := treemap.NewCompactTreeBuilder()
+	b.Format = s.Format
+
+	if err := s.readNodes(ctx, reader, b.Add); err != nil {
+		return nil, err
+	}
+
+	tree := b.Build()
+	if len(tree.Nodes) == 1 {
+		return nil, errors.New("no roots, empty input")
+	}
+
+	return tree, nil
+}
+
+// readNodes reads records and passes nodes to add one by one
+func (s *CSVTreeParser) readNodes(ctx context.Context, reader io.Reader, add func(node treemap.Node)) error {
+	r := s.newReader(reader)
+
+	// total is unknown
+	bar := treemap.ProgressOrNop(s.Progress)
+	bar.Start("Parsing CSV records", -1)
+	defer bar.Finish()
+
+	// columns mapped by name are found in header
+	cols := defaultColumns
+	if !s.Columns.hasNames() {
+		var err error
+		if cols, err = s.Columns.indexes(nil); err != nil {
+			return err
+		}
 	}
-	r.LazyQuotes = true
 
-	// Create progress bar with unknown total
-	bar := progressbar.Default(-1)
-	bar.Describe("Parsing CSV records")
 	count := 0
 	for {
+		if count%checkContextEvery == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+
 		record, err := r.Read()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("error reading CSV: %w", err)
+			return fmt.Errorf("error reading CSV: %w", err)
 		}
 
-		if len(record) == 0 {
-			return nil, errors.New("no values in row")
+		if count == 0 && s.Columns.hasNames() {
+			if cols, err = s.Columns.indexes(record); err != nil {
+				return err
+			}
+			count++
+			continue
 		}
 
-		if (record[0] == "path" || record[0] == "full_path") && count == 0 {
+		if count == 0 && (s.Header || isHeader(record, cols)) {
 			// skip header
 			count++
 			continue
 		}
 
 		count++
-		path := record[0]
-		var size float64
-		if len(record) >= 2 {
-			size, err = strconv.ParseFloat(record[1], 64)
-			if err != nil {
-				return nil, fmt.Errorf("size(%s) is not float: %w", record[1], err)
-			}
+		node, err := parseRecord(record, cols)
+		if err != nil {
+			return err
 		}
 
-		if strings.HasSuffix(path, "/") {
+		if s.Format.HasTrailingSeparator(node.Path) {
 			continue
 		}
 
-		// Get node name from path
-		parts := strings.Split(path, "/")
-		name := parts[len(parts)-1]
+		add(node)
+		bar.Add(1)
+	}
+
+	return nil
+}
 
-		// Process node
-		if existingNode, ok := tree.Nodes[path]; ok {
-			tree.Nodes[path] = treemap.Node{
-				Path: existingNode.Path,
-				Name: existingNode.Name,
-				Size: existingNode.Size + size,
-			}
-		} else {
-			tree.Nodes[path] = treemap.Node{
-				Path: path,
-				Name: name,
-				Size: size,
-			}
+// ParseFile parses a CSV file into a tree structure
+func (s *CSVTreeParser) ParseFile(filepath string) (*treemap.Tree, error) {
+	return s.ParseFileContext(context.Background(), filepath)
+}
+
+// ParseFileContext is same as ParseFile, but stops with context error when context is done.
+func (s *CSVTreeParser) ParseFileContext(ctx context.Context, filepath string) (*treemap.Tree, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open file: %w", err)
+	}
+	defer file.Close()
+
+	return s.ParseReaderContext(ctx, file)
+}
+
+// ParseFileCompact parses a CSV file into a compact tree structure
+func (s *CSVTreeParser) ParseFileCompact(filepath string) (*treemap.CompactTree, error) {
+	return s.ParseFileCompactContext(context.Background(), filepath)
+}
+
+// ParseFileCompactContext is same as ParseFileCompact, but stops with context error when context is done.
+func (s *CSVTreeParser) ParseFileCompactContext(ctx context.Context, filepath string) (*treemap.CompactTree, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open file: %w", err)
+	}
+	defer file.Close()
+
+	return s.ParseReaderCompactContext(ctx, file)
+}
+
+func (s *CSVTreeParser) newReader(reader io.Reader) *csv.Reader {
+	return newCSVReader(reader, s.Comma)
+}
+
+// newCSVReader reads records with any number of fields, ',' is used when comma is not set
+func newCSVReader(reader io.Reader, comma rune) *csv.Reader {
+	r := csv.NewReader(reader)
+	if comma != 0 {
+		r.Comma = comma
+	}
+	r.LazyQuotes = true
+	r.FieldsPerRecord = -1
+	return r
+}
+
+func isHeader(record []string, cols columnIndexes) bool {
+	path, ok := cols.cell(record, cols.path)
+	return ok && (path == "path" || path == "full_path")
+}
+
+// parseRecord makes node out of path, size, optional heat and label columns.
+// Name is set only from label.
+func parseRecord(record []string, cols columnIndexes) (treemap.Node, error) {
+	if len(record) == 0 {
+		return treemap.Node{}, errors.New("no values in row")
+	}
+
+	path, ok := cols.cell(record, cols.path)
+	if !ok {
+		return treemap.Node{}, fmt.Errorf("no path in column(%d)", cols.path)
+	}
+	node := treemap.Node{Path: path}
+
+	if v, ok := cols.cell(record, cols.size); ok {
+		size, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return treemap.Node{}, fmt.Errorf("size(%s) is not float: %w", v, err)
 		}
+		node.Size = size
+	}
 
-		// Build parent-child relationships
-		hasParent[parts[0]] = false
+	// empty heat is missing heat, as in columns mapped by index or name
+	if v, ok := cols.cell(record, cols.heat); ok && v != "" {
+		heat, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return treemap.Node{}, fmt.Errorf("heat(%s) is not float: %w", v, err)
+		}
+		node.Heat = heat
+		node.HasHeat = true
+	}
+
+	if v, ok := cols.cell(record, cols.label); ok {
+		node.Name = v
+	}
 
-		for parent, i := parts[0], 1; i < len(parts); i++ {
-			child := parent + "/" + parts[i]
+	return node, nil
+}
 
-			if _, ok := tree.Nodes[parent]; !ok {
-				parentName := parts[i-1]
-				tree.Nodes[parent] = treemap.Node{
-					Path: parent,
-					Name: parentName,
-				}
-			}
+// parseNodes parses all CSV records in string into nodes.
+func parseNodes(in string) ([]treemap.Node, error) {
+	r := (&CSVTreeParser{}).newReader(strings.NewReader(in))
 
-			// Initialize the unique children map for this parent if needed
-			if _, ok := uniqueChildren[parent]; !ok {
-				uniqueChildren[parent] = make(map[string]bool)
-			}
+	var nodes []treemap.Node
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading CSV: %w", err)
+		}
+
+		node, err := parseRecord(record, defaultColumns)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	return nodes, nil
+}
+
+// makeTree builds tree from nodes, adding missing parents.
+func makeTree(nodes []treemap.Node) (*treemap.Tree, error) {
+	b := newTreeBuilder()
+	for _, node := range nodes {
+		b.add(node)
+	}
+	return b.build()
+}
+
+// treeBuilder accumulates nodes and edges to parents one path at a time.
+type treeBuilder struct {
+	tree *treemap.Tree
+
+	// for finding roots
+	hasParent map[string]bool
+	// for tracking unique children
+	uniqueChildren map[string]map[string]bool
+
+	// setNames sets names of nodes from leaf of path
+	setNames bool
+}
+
+func newTreeBuilder() *treeBuilder {
+	return &treeBuilder{
+		tree: &treemap.Tree{
+			Nodes: make(map[string]treemap.Node),
+			To:    make(map[string][]string),
+		},
+		hasParent:      make(map[string]bool),
+		uniqueChildren: make(map[string]map[string]bool),
+	}
+}
+
+// add node to tree and all missing parents.
+// Duplicate nodes sum their sizes and average their heat weighted by size.
+func (b *treeBuilder) add(node treemap.Node) {
+	tree := b.tree
+	path := node.Path
+
+	// Get node name from path
+	parts := tree.Format.Split(path)
+	if b.setNames && node.Name == "" {
+		node.Name = parts[len(parts)-1]
+	}
+
+	// Process node
+	if existingNode, ok := tree.Nodes[path]; ok {
+		tree.Nodes[path] = mergeNodes(existingNode, node)
+	} else {
+		tree.Nodes[path] = node
+	}
+
+	// Build parent-child relationships
+	if _, ok := b.hasParent[parts[0]]; !ok {
+		b.hasParent[parts[0]] = false
+	}
 
-			// Only add the child if we haven't seen it before for this parent
-			if !uniqueChildren[parent][child] {
-				tree.To[parent] = append(tree.To[parent], child)
-				uniqueChildren[parent][child] = true
+	for parent, i := parts[0], 1; i < len(parts); i++ {
+		child := tree.Format.Join(parent, parts[i])
+
+		if _, ok := tree.Nodes[parent]; !ok {
+			parentNode := treemap.Node{Path: parent}
+			if b.setNames {
+				parentNode.Name = parts[i-1]
 			}
-			hasParent[child] = true
+			tree.Nodes[parent] = parentNode
+		}
 
-			parent = child
+		// Initialize the unique children map for this parent if needed
+		if _, ok := b.uniqueChildren[parent]; !ok {
+			b.uniqueChildren[parent] = make(map[string]bool)
 		}
 
-		bar.Add(1)
+		// Only add the child if we haven't seen it before for this parent
+		if !b.uniqueChildren[parent][child] {
+			tree.To[parent] = append(tree.To[parent], child)
+			b.uniqueChildren[parent][child] = true
+		}
+		b.hasParent[child] = true
+
+		parent = child
 	}
+}
 
-	// Finish the progress bar
-	bar.Finish()
+// build finds roots and returns tree.
+// Multiple roots are joined under fake root.
+func (b *treeBuilder) build() (*treemap.Tree, error) {
+	tree := b.tree
 
-	// Find roots
 	var roots []string
-	for node, has := range hasParent {
+	for node, has := range b.hasParent {
 		if !has {
 			roots = append(roots, node)
 		}
 	}
+	sort.Strings(roots)
 
 	switch {
 	case len(roots) == 0:
@@ -148,13 +356,21 @@ func (s *CSVTreeParser) ParseReader(reader io.Reader) (*treemap.Tree, error) {
 	return tree, nil
 }
 
-// ParseFile parses a CSV file into a tree structure
-func (s *CSVTreeParser) ParseFile(filepath string) (*treemap.Tree, error) {
-	file, err := os.Open(filepath)
-	if err != nil {
-		return nil, fmt.Errorf("cannot open file: %w", err)
+func mergeNodes(a, b treemap.Node) treemap.Node {
+	n := a
+	n.Size = a.Size + b.Size
+
+	switch {
+	case a.HasHeat && b.HasHeat:
+		if total := a.Size + b.Size; total != 0 {
+			n.Heat = (a.Heat*a.Size + b.Heat*b.Size) / total
+		} else {
+			n.Heat = b.Heat
+		}
+	case b.HasHeat:
+		n.Heat = b.Heat
+		n.HasHeat = true
 	}
-	defer file.Close()
 
-	return s.ParseReader(file)
+	return n
 }
@@ -1,6 +1,9 @@
 package parser
 
 import (
+	"context"
+	"errors"
+	"reflect"
 	"sort"
 	"strings"
 	"testing"
@@ -120,6 +123,23 @@ func TestMakeTree(t *testing.T) {
 				Root: "a",
 			},
 		},
+		{
+			name: "when duplicate nodes with heat, then sums sizes and weights heat by size",
+			nodes: []treemap.Node{
+				{Path: "a/b", Size: 1, Heat: 1, HasHeat: true},
+				{Path: "a/b", Size: 3, Heat: 5, HasHeat: true},
+			},
+			expTree: &treemap.Tree{
+				Nodes: map[string]treemap.Node{
+					"a":   {Path: "a"},
+					"a/b": {Path: "a/b", Size: 4, Heat: 4, HasHeat: true},
+				},
+				To: map[string][]string{
+					"a": {"a/b"},
+				},
+				Root: "a",
+			},
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -168,8 +188,10 @@ func TestParseNodes(t *testing.T) {
 			in:   "a/b/c,10,11",
 			expNodes: []treemap.Node{
 				{
-					Path: "a/b/c",
-					Size: 10,
+					Path:    "a/b/c",
+					Size:    10,
+					Heat:    11,
+					HasHeat: true,
 				},
 			},
 		},
@@ -178,8 +200,10 @@ func TestParseNodes(t *testing.T) {
 			in:   "a\"b\",1,1",
 			expNodes: []treemap.Node{
 				{
-					Path: "a\"b\"",
-					Size: 1,
+					Path:    "a\"b\"",
+					Size:    1,
+					Heat:    1,
+					HasHeat: true,
 				},
 			},
 		},
@@ -190,18 +214,45 @@ func TestParseNodes(t *testing.T) {
 				{
 					Path:    "ab",
 					Size:    1,
+					Heat:    1,
+					HasHeat: true,
+				},
+			},
+		},
+		{
+			name: "when no heat, then has no heat",
+			in:   "a/b/c,10",
+			expNodes: []treemap.Node{
+				{
+					Path: "a/b/c",
+					Size: 10,
+				},
+			},
+		},
+		{
+			name: "when empty heat, then has no heat",
+			in:   "a/b,1,",
+			expNodes: []treemap.Node{
+				{
+					Path: "a/b",
+					Size: 1,
 				},
 			},
 		},
+		{
+			name:   "when wrong heat, then error",
+			in:     "a,1,x",
+			expErr: "heat(x) is not float",
+		},
 		{
 			name:   "when wrong number, then error",
 			in:     ",,\n\n",
 			expErr: "is not float",
 		},
 		{
-			name:   "when wrong number, then error",
-			in:     ",1,\n\n",
-			expErr: "is not float",
+			name:     "when empty path and heat, then has no heat",
+			in:       ",1,\n\n",
+			expNodes: []treemap.Node{{Size: 1}},
 		},
 	}
 	for _, tc := range tests {
@@ -272,3 +323,204 @@ func eqTree(a, b treemap.Tree) bool {
 
 	return true
 }
+
+func TestParseReaderCompact(t *testing.T) {
+	in := "a/b/c,1,2\na/b/d,3\na/e,4,5\na/b/c,1,4\n"
+
+	parser := CSVTreeParser{}
+	expTree, err := parser.ParseReader(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compact, err := parser.ParseReaderCompact(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tree := compact.Tree(); !eqTree(*expTree, *tree) {
+		t.Errorf("tree: exp(%#v) != got(%#v)", expTree, tree)
+	}
+}
+
+func TestParseReaderCompactEmpty(t *testing.T) {
+	_, err := (&CSVTreeParser{}).ParseReaderCompact(strings.NewReader(""))
+	assertError(t, err, "no roots")
+}
+
+func TestParseReaderContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	parser := CSVTreeParser{}
+	if _, err := parser.ParseReaderContext(ctx, strings.NewReader("a/b,1\n")); !errors.Is(err, context.Canceled) {
+		t.Errorf("exp(%v) != got(%v)", context.Canceled, err)
+	}
+	if _, err := parser.ParseReaderCompactContext(ctx, strings.NewReader("a/b,1\n")); !errors.Is(err, context.Canceled) {
+		t.Errorf("compact: exp(%v) != got(%v)", context.Canceled, err)
+	}
+}
+
+func TestParseReaderPathFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   treemap.PathFormat
+		in       string
+		expRoot  string
+		expTo    map[string][]string
+		expNames map[string]string
+	}{
+		{
+			name:    "java packages",
+			format:  treemap.PathFormat{Separator: "."},
+			in:      "com.example.Foo,1\ncom.example.Bar,2\n",
+			expRoot: "com",
+			expTo: map[string][]string{
+				"com":         {"com.example"},
+				"com.example": {"com.example.Foo", "com.example.Bar"},
+			},
+			expNames: map[string]string{"com.example.Foo": "Foo"},
+		},
+		{
+			name:    "rust modules",
+			format:  treemap.PathFormat{Separator: "::"},
+			in:      "std::io::Read,1\nstd::fs,2\n",
+			expRoot: "std",
+			expTo: map[string][]string{
+				"std":     {"std::io", "std::fs"},
+				"std::io": {"std::io::Read"},
+			},
+			expNames: map[string]string{"std::io::Read": "Read"},
+		},
+		{
+			name:    "windows paths",
+			format:  treemap.PathFormat{Separator: `\`},
+			in:      "C:\\a\\b,1\nC:\\c\\,2\n",
+			expRoot: "C:",
+			expTo: map[string][]string{
+				"C:":   {`C:\a`},
+				`C:\a`: {`C:\a\b`},
+			},
+			expNames: map[string]string{`C:\a\b`: "b"},
+		},
+		{
+			name:    "escaped separator",
+			format:  treemap.PathFormat{Escape: '\\'},
+			in:      "a/b\\/c,1\na/d,2\n",
+			expRoot: "a",
+			expTo: map[string][]string{
+				"a": {`a/b\/c`, "a/d"},
+			},
+			expNames: map[string]string{`a/b\/c`: `b\/c`},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			parser := CSVTreeParser{Format: tc.format}
+			tree, err := parser.ParseReader(strings.NewReader(tc.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tree.Root != tc.expRoot {
+				t.Errorf("root: exp(%s) != got(%s)", tc.expRoot, tree.Root)
+			}
+			if !reflect.DeepEqual(tc.expTo, tree.To) {
+				t.Errorf("edges: exp(%#v) != got(%#v)", tc.expTo, tree.To)
+			}
+			for path, name := range tc.expNames {
+				if got := tree.Nodes[path].Name; got != name {
+					t.Errorf("name of %s: exp(%s) != got(%s)", path, name, got)
+				}
+			}
+
+			compact, err := parser.ParseReaderCompact(strings.NewReader(tc.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := compact.Tree(); !reflect.DeepEqual(tc.expTo, got.To) {
+				t.Errorf("compact edges: exp(%#v) != got(%#v)", tc.expTo, got.To)
+			}
+		})
+	}
+}
+
+func TestParseReaderColumns(t *testing.T) {
+	tests := []struct {
+		name     string
+		parser   CSVTreeParser
+		in       string
+		expNodes map[string]treemap.Node
+		expErr   string
+	}{
+		{
+			name:   "by name",
+			parser: CSVTreeParser{Columns: CSVColumns{Path: "module", Size: "bytes", Heat: "coverage", Label: "owner"}},
+			in:     "module,bytes,owner,coverage\na/b,10,alice,0.5\na/c,5,,\n",
+			expNodes: map[string]treemap.Node{
+				"a/b": {Path: "a/b", Name: "alice", Size: 10, Heat: 0.5, HasHeat: true},
+				"a/c": {Path: "a/c", Name: "c", Size: 5},
+			},
+		},
+		{
+			name:   "by index",
+			parser: CSVTreeParser{Columns: CSVColumns{Path: "1", Size: "0"}},
+			in:     "10,a/b\n5,a/c\n",
+			expNodes: map[string]treemap.Node{
+				"a/b": {Path: "a/b", Name: "b", Size: 10},
+				"a/c": {Path: "a/c", Name: "c", Size: 5},
+			},
+		},
+		{
+			name:   "by index with header",
+			parser: CSVTreeParser{Comma: '\t', Header: true, Columns: CSVColumns{Path: "0", Size: "2"}},
+			in:     "module\towner\tbytes\na/b\talice\t10\n",
+			expNodes: map[string]treemap.Node{
+				"a/b": {Path: "a/b", Name: "b", Size: 10},
+			},
+		},
+		{
+			name:   "by index with header not skipped",
+			parser: CSVTreeParser{Columns: CSVColumns{Path: "0", Size: "1"}},
+			in:     "module,bytes\na/b,10\n",
+			expErr: "size(bytes) is not float",
+		},
+		{
+			name:   "tsv with byte order mark",
+			parser: CSVTreeParser{Comma: '\t', Columns: CSVColumns{Path: "path", Size: "size"}},
+			in:     "\ufeffpath\tsize\na,b/c\t3\n",
+			expNodes: map[string]treemap.Node{
+				"a,b/c": {Path: "a,b/c", Name: "c", Size: 3},
+			},
+		},
+		{
+			name:   "column not in header",
+			parser: CSVTreeParser{Columns: CSVColumns{Path: "module", Size: "size"}},
+			in:     "module,bytes\na,1\n",
+			expErr: "column(size) not found in header",
+		},
+		{
+			name:   "no path column",
+			parser: CSVTreeParser{Columns: CSVColumns{Size: "1"}},
+			in:     "a,1\n",
+			expErr: "path column is not set",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tree, err := tc.parser.ParseReader(strings.NewReader(tc.in))
+			assertError(t, err, tc.expErr)
+			if err != nil {
+				return
+			}
+
+			for path, exp := range tc.expNodes {
+				if got := tree.Nodes[path]; got != exp {
+					t.Errorf("%s: exp(%#v) != got(%#v)", path, exp, got)
+				}
+			}
+		})
+	}
+}
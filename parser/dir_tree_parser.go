@@ -0,0 +1,295 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/MazenAlkhatib/treemap"
+)
+
+// SymlinkPolicy is how DirTreeParser treats symbolic links
+type SymlinkPolicy int
+
+const (
+	SkipSymlinks   SymlinkPolicy = iota // symbolic links are not in tree
+	CountSymlinks                       // symbolic links are files sized by link itself
+	FollowSymlinks                      // symbolic links are files or directories they point to, each directory is walked once
+)
+
+// ParseSymlinkPolicy parses policy by name: skip, count or follow
+func ParseSymlinkPolicy(s string) (SymlinkPolicy, error) {
+	switch s {
+	case "skip":
+		return SkipSymlinks, nil
+	case "count":
+		return CountSymlinks, nil
+	case "follow":
+		return FollowSymlinks, nil
+	default:
+		return 0, fmt.Errorf("invalid symlink policy(%s), expected skip, count or follow", s)
+	}
+}
+
+// maxSymlinkDepth limits nested symbolic links followed when directories can not be told apart, as in path resolution of Linux
+const maxSymlinkDepth = 40
+
+// DirTreeParser walks directory into a tree structure, with nodes for directories and files sized by files.
+// Path of root is name of directory, such as "src/foo/bar.go" in walk of "src".
+// Directories, files and symbolic links that can not be read are skipped as in du, only root that can not be read is error.
+type DirTreeParser struct {
+	DiskUsage bool          // sizes of files are space allocated on disk as in du, apparent sizes as in ls -l when not set
+	Symlinks  SymlinkPolicy // symbolic links are skipped when not set
+	Hidden    bool          // includes files and directories with names starting with dot
+	Gitignore bool          // excludes files and directories matching .gitignore files in walked directories
+	Exclude   []string      // excludes files and directories matching patterns in format of .gitignore, relative to root
+	Warnings  io.Writer     // skipped directories, files and symbolic links are reported to it, such as os.Stderr, not reported when not set
+	Progress  treemap.Progress
+}
+
+// ParseDir walks directory in file system of operating system into a tree structure
+func (s *DirTreeParser) ParseDir(dir string) (*treemap.Tree, error) {
+	return s.ParseDirContext(context.Background(), dir)
+}
+
+// ParseDirContext is same as ParseDir, but stops with context error when context is done.
+func (s *DirTreeParser) ParseDirContext(ctx context.Context, dir string) (*treemap.Tree, error) {
+	root, err := dirName(dir)
+	if err != nil {
+		return nil, err
+	}
+	return s.ParseFSContext(ctx, os.DirFS(dir), root)
+}
+
+// ParseDirCompact walks directory in file system of operating system into a compact tree structure, for large directories
+func (s *DirTreeParser) ParseDirCompact(dir string) (*treemap.CompactTree, error) {
+	return s.ParseDirCompactContext(context.Background(), dir)
+}
+
+// ParseDirCompactContext is same as ParseDirCompact, but stops with context error when context is done.
+func (s *DirTreeParser) ParseDirCompactContext(ctx context.Context, dir string) (*treemap.CompactTree, error) {
+	root, err := dirName(dir)
+	if err != nil {
+		return nil, err
+	}
+	return s.ParseFSCompactContext(ctx, os.DirFS(dir), root)
+}
+
+// ParseFS walks file system from its top directory into a tree structure, root is path of root node
+func (s *DirTreeParser) ParseFS(fsys fs.FS, root string) (*treemap.Tree, error) {
+	return s.ParseFSContext(context.Background(), fsys, root)
+}
+
+// ParseFSContext is same as ParseFS, but stops with context error when context is done.
+func (s *DirTreeParser) ParseFSContext(ctx context.Context, fsys fs.FS, root string) (*treemap.Tree, error) {
+	b := newTreeBuilder()
+	b.setNames = true
+
+	if err := s.walk(ctx, fsys, root, b.add); err != nil {
+		return nil, err
+	}
+
+	return b.build()
+}
+
+// ParseFSCompact walks file system from its top directory into a compact tree structure, root is path of root node
+func (s *DirTreeParser) ParseFSCompact(fsys fs.FS, root string) (*treemap.CompactTree, error) {
+	return s.ParseFSCompactContext(context.Background(), fsys, root)
+}
+
+// ParseFSCompactContext is same as ParseFSCompact, but stops with context error when context is done.
+func (s *DirTreeParser) ParseFSCompactContext(ctx context.Context, fsys fs.FS, root string) (*treemap.CompactTree, error) {
+	b := treemap.NewCompactTreeBuilder()
+
+	if err := s.walk(ctx, fsys, root, b.Add); err != nil {
+		return nil, err
+	}
+
+	return b.Build(), nil
+}
+
+// dirName is name of directory for root of tree, such as "bar" for "foo/bar/" or "."
+func dirName(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("cannot find directory(%s): %w", dir, err)
+	}
+
+	name := filepath.Base(abs)
+	if name == string(filepath.Separator) {
+		return "root", nil
+	}
+	return name, nil
+}
+
+// walker is state of single walk
+type walker struct {
+	*DirTreeParser
+
+	fsys fs.FS
+	add  func(node treemap.Node)
+	bar  treemap.Progress
+
+	// visited directories, when file system reports their identity
+	visited map[fileID]bool
+}
+
+func (s *DirTreeParser) walk(ctx context.Context, fsys fs.FS, root string, add func(node treemap.Node)) error {
+	if root == "" {
+		return errors.New("root is not set")
+	}
+
+	var rules []ignoreRule
+	for _, pattern := range s.Exclude {
+		rule, err := parseIgnoreRule(".", pattern)
+		if err != nil {
+			return err
+		}
+		rules = append(rules, rule)
+	}
+
+	bar := treemap.ProgressOrNop(s.Progress)
+	bar.Start("Walking directory", -1)
+	defer bar.Finish()
+
+	w := walker{DirTreeParser: s, fsys: fsys, add: add, bar: bar, visited: make(map[fileID]bool)}
+
+	if info, err := fs.Stat(fsys, "."); err != nil {
+		return fmt.Errorf("cannot read directory(%s): %w", root, err)
+	} else if id, ok := fileStatID(info); ok {
+		w.visited[id] = true
+	}
+
+	add(treemap.Node{Path: root})
+	return w.walkDir(ctx, ".", root, rules, 0)
+}
+
+// walkDir adds children of directory name in file system, path is path of directory in tree
+func (w *walker) walkDir(ctx context.Context, name, dirPath string, rules []ignoreRule, symlinkDepth int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	entries, err := fs.ReadDir(w.fsys, name)
+	if err != nil {
+		err = fmt.Errorf("cannot read directory(%s): %w", name, err)
+		if name == "." {
+			return err
+		}
+		w.skip(err)
+		return nil
+	}
+
+	if w.Gitignore {
+		if dirRules, err := w.readGitignore(name); err != nil {
+			return err
+		} else if len(dirRules) > 0 {
+			// rules of parent are not changed, they are shared with siblings
+			rules = append(rules[:len(rules):len(rules)], dirRules...)
+		}
+	}
+
+	for _, entry := range entries {
+		if !w.Hidden && strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		childName := path.Join(name, entry.Name())
+		childPath := dirPath + "/" + entry.Name()
+		childSymlinkDepth := symlinkDepth
+
+		info, err := entry.Info()
+		if err != nil {
+			w.skip(fmt.Errorf("cannot read file(%s): %w", childName, err))
+			continue
+		}
+
+		if entry.Type()&fs.ModeSymlink != 0 {
+			switch w.Symlinks {
+			case SkipSymlinks:
+				continue
+			case FollowSymlinks:
+				if info, err = fs.Stat(w.fsys, childName); err != nil {
+					w.skip(fmt.Errorf("cannot follow symlink(%s): %w", childName, err))
+					continue
+				}
+				childSymlinkDepth++
+			}
+		}
+
+		if isIgnored(rules, childName, info.IsDir()) {
+			continue
+		}
+
+		if !info.IsDir() {
+			w.add(treemap.Node{Path: childPath, Size: float64(w.fileSize(info))})
+			w.bar.Add(1)
+			continue
+		}
+
+		// directories reached by symlinks more than once are walked once, such as links to ancestors
+		if id, ok := fileStatID(info); ok {
+			if w.visited[id] {
+				continue
+			}
+			w.visited[id] = true
+		} else if childSymlinkDepth > maxSymlinkDepth {
+			w.skip(fmt.Errorf("too many levels of symbolic links in directory(%s)", childName))
+			continue
+		}
+
+		w.add(treemap.Node{Path: childPath})
+		w.bar.Add(1)
+
+		if err := w.walkDir(ctx, childName, childPath, rules, childSymlinkDepth); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// skip reports error of skipped directory, file or symbolic link
+func (w *walker) skip(err error) {
+	if w.Warnings != nil {
+		fmt.Fprintf(w.Warnings, "%v, skipped\n", err)
+	}
+}
+
+func (w *walker) readGitignore(dir string) ([]ignoreRule, error) {
+	f, err := w.fsys.Open(path.Join(dir, ".gitignore"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot open file: %w", err)
+	}
+	defer f.Close()
+
+	return parseIgnoreRules(dir, f)
+}
+
+// fileSize is apparent size of file or space allocated on disk, which is rounded up to blocks when file system does not report it
+func (s *DirTreeParser) fileSize(info fs.FileInfo) int64 {
+	if !s.DiskUsage {
+		return info.Size()
+	}
+	if blocks, ok := fileStatBlocks(info); ok {
+		return blocks * 512
+	}
+	return (info.Size() + diskBlockSize - 1) / diskBlockSize * diskBlockSize
+}
+
+// diskBlockSize is common size of block of file systems
+const diskBlockSize = 4096
+
+// fileID is identity of file in file system of operating system, same for hard links
+type fileID struct {
+	dev, ino uint64
+}
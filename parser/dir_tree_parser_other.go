@@ -0,0 +1,11 @@
+//go:build !unix
+
+package parser
+
+import "io/fs"
+
+// fileStatBlocks is not reported by file systems on this platform
+func fileStatBlocks(info fs.FileInfo) (int64, bool) { return 0, false }
+
+// fileStatID is not reported by file systems on this platform
+func fileStatID(info fs.FileInfo) (fileID, bool) { return fileID{}, false }
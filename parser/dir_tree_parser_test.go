@@ -0,0 +1,231 @@
+package parser
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/MazenAlkhatib/treemap"
+)
+
+func TestDirTreeParser(t *testing.T) {
+	fsys := fstest.MapFS{
+		"go.mod":                 {Data: make([]byte, 10)},
+		"main.go":                {Data: make([]byte, 5000)},
+		".git/HEAD":              {Data: make([]byte, 20)},
+		"app.log":                {Data: make([]byte, 30)},
+		"keep.log":               {Data: make([]byte, 40)},
+		"pkg/a.go":               {Data: make([]byte, 50)},
+		"pkg/.gitignore":         {Data: []byte("/gen\n")},
+		"pkg/gen/b.go":           {Data: make([]byte, 60)},
+		"vendor/x/y.go":          {Data: make([]byte, 70)},
+		"empty":                  {Mode: os.ModeDir},
+		".gitignore":             {Data: []byte("*.log\n!keep.log\n")},
+		"node_modules/left-pad":  {Data: make([]byte, 80)},
+		"docs/node_modules/file": {Data: make([]byte, 90)},
+	}
+
+	tests := []struct {
+		name   string
+		parser DirTreeParser
+		exp    string
+	}{
+		{
+			name:   "when default, then hidden are skipped",
+			parser: DirTreeParser{},
+			exp:    "src,0\nsrc/app.log,30\nsrc/docs,0\nsrc/docs/node_modules,0\nsrc/docs/node_modules/file,90\nsrc/empty,0\nsrc/go.mod,10\nsrc/keep.log,40\nsrc/main.go,5000\nsrc/node_modules,0\nsrc/node_modules/left-pad,80\nsrc/pkg,0\nsrc/pkg/a.go,50\nsrc/pkg/gen,0\nsrc/pkg/gen/b.go,60\nsrc/vendor,0\nsrc/vendor/x,0\nsrc/vendor/x/y.go,70\n",
+		},
+		{
+			name:   "when hidden, then hidden are included",
+			parser: DirTreeParser{Hidden: true, Exclude: []string{"/*", "!.git"}},
+			exp:    "src,0\nsrc/.git,0\nsrc/.git/HEAD,20\n",
+		},
+		{
+			name:   "when gitignore and exclude, then ignored are skipped",
+			parser: DirTreeParser{Gitignore: true, Exclude: []string{"node_modules/", "vendor", "empty"}},
+			exp:    "src,0\nsrc/docs,0\nsrc/go.mod,10\nsrc/keep.log,40\nsrc/main.go,5000\nsrc/pkg,0\nsrc/pkg/a.go,50\n",
+		},
+		{
+			name:   "when disk usage, then sizes are rounded to blocks",
+			parser: DirTreeParser{DiskUsage: true, Exclude: []string{"/*", "!main.go", "!go.mod"}},
+			exp:    "src,0\nsrc/go.mod,4096\nsrc/main.go,8192\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tree, err := tc.parser.ParseFS(fsys, "src")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expTree, err := (&CSVTreeParser{}).ParseReader(strings.NewReader(tc.exp))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !eqTree(*expTree, *tree) {
+				t.Errorf("tree: exp(%#v) != got(%#v)", expTree, tree)
+			}
+
+			compact, err := tc.parser.ParseFSCompact(fsys, "src")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := compact.Tree(); !eqTree(*expTree, *got) {
+				t.Errorf("compact tree: exp(%#v) != got(%#v)", expTree, got)
+			}
+		})
+	}
+}
+
+func TestDirTreeParserSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "a", "b"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a", "b", "c"), make([]byte, 10), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for link, target := range map[string]string{"a/b/loop": "..", "a/file": "b/c", "a/dir": "b"} {
+		if err := os.Symlink(target, filepath.Join(dir, link)); err != nil {
+			t.Skip(err)
+		}
+	}
+
+	tests := []struct {
+		policy SymlinkPolicy
+		exp    []string
+	}{
+		{policy: SkipSymlinks, exp: []string{"a", "a/b", "a/b/c"}},
+		{policy: CountSymlinks, exp: []string{"a", "a/b", "a/b/c", "a/b/loop", "a/dir", "a/file"}},
+		{policy: FollowSymlinks, exp: []string{"a", "a/b", "a/b/c", "a/file"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(strings.Join(tc.exp, ","), func(t *testing.T) {
+			tree, err := (&DirTreeParser{Symlinks: tc.policy}).ParseDir(filepath.Join(dir, "a"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for path := range tree.Nodes {
+				got = append(got, path)
+			}
+			sort.Strings(got)
+
+			if !reflect.DeepEqual(tc.exp, got) {
+				t.Errorf("exp(%v) != got(%v)", tc.exp, got)
+			}
+			if exp, got := 10.0, tree.Nodes["a/b/c"].Size; exp != got {
+				t.Errorf("size: exp(%v) != got(%v)", exp, got)
+			}
+		})
+	}
+}
+
+func TestParseSymlinkPolicy(t *testing.T) {
+	if p, err := ParseSymlinkPolicy("follow"); err != nil || p != FollowSymlinks {
+		t.Errorf("exp(%v) != got(%v, %v)", FollowSymlinks, p, err)
+	}
+	_, err := ParseSymlinkPolicy("x")
+	assertError(t, err, "invalid symlink policy(x)")
+}
+
+func TestDirTreeParserDiskUsage(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "f"), make([]byte, 10), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tree, err := (&DirTreeParser{DiskUsage: true}).ParseDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var f treemap.Node
+	for _, node := range tree.Nodes {
+		if node.Name == "f" {
+			f = node
+		}
+	}
+	// size allocated on disk is whole blocks, it can be zero for files inlined in metadata
+	if int64(f.Size)%512 != 0 {
+		t.Errorf("exp(multiple of 512) != got(%v)", f.Size)
+	}
+}
+
+// unreadableFS fails to read directory
+type unreadableFS struct {
+	fstest.MapFS
+	dir string
+}
+
+func (f unreadableFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if name == f.dir {
+		return nil, fs.ErrPermission
+	}
+	return f.MapFS.ReadDir(name)
+}
+
+func TestDirTreeParserSkipsUnreadable(t *testing.T) {
+	fsys := unreadableFS{
+		MapFS: fstest.MapFS{
+			"b/c":      {Data: make([]byte, 2)},
+			"secret/d": {Data: make([]byte, 3)},
+		},
+		dir: "secret",
+	}
+
+	var warnings strings.Builder
+	tree, err := (&DirTreeParser{Warnings: &warnings}).ParseFS(fsys, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := tree.Nodes["a/secret/d"]; ok {
+		t.Errorf("unreadable directory is walked")
+	}
+	if exp, got := 2.0, tree.Nodes["a/b/c"].Size; exp != got {
+		t.Errorf("size: exp(%v) != got(%v)", exp, got)
+	}
+	if exp := "cannot read directory(secret): permission denied, skipped\n"; warnings.String() != exp {
+		t.Errorf("exp(%q) != got(%q)", exp, warnings.String())
+	}
+
+	fsys.dir = "."
+	_, err = (&DirTreeParser{}).ParseFS(fsys, "a")
+	assertError(t, err, "cannot read directory(.)")
+}
+
+func TestDirTreeParserSkipsDanglingSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "f"), make([]byte, 10), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("missing", filepath.Join(dir, "dangling")); err != nil {
+		t.Skip(err)
+	}
+
+	var warnings strings.Builder
+	tree, err := (&DirTreeParser{Symlinks: FollowSymlinks, Warnings: &warnings}).ParseDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := filepath.Base(dir)
+	if _, ok := tree.Nodes[root+"/dangling"]; ok {
+		t.Errorf("dangling symlink is in tree")
+	}
+	if exp, got := 10.0, tree.Nodes[root+"/f"].Size; exp != got {
+		t.Errorf("size: exp(%v) != got(%v)", exp, got)
+	}
+	if !strings.Contains(warnings.String(), "cannot follow symlink(dangling)") {
+		t.Errorf("dangling symlink is not reported: %q", warnings.String())
+	}
+}
@@ -0,0 +1,26 @@
+//go:build unix
+
+package parser
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// fileStatBlocks is number of 512 byte blocks allocated for file, false when file is not from file system of operating system
+func fileStatBlocks(info fs.FileInfo) (int64, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return int64(st.Blocks), true
+}
+
+// fileStatID is identity of file, false when file is not from file system of operating system
+func fileStatID(info fs.FileInfo) (fileID, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileID{}, false
+	}
+	return fileID{dev: uint64(st.Dev), ino: uint64(st.Ino)}, true
+}
@@ -0,0 +1,211 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/MazenAlkhatib/treemap"
+)
+
+// EdgeListParser parses CSV records of id, parent id, optional size and optional label into tree,
+// such as org charts or spans. Roots have empty parent id, there can be multiple roots.
+// Header is skipped when first column of first record is "id".
+// Paths of nodes are joined ids of ancestors, such as "ceo/cto/alice", names are labels or ids.
+type EdgeListParser struct {
+	Comma    rune               // such as '\t' for TSV, ',' when not set
+	Format   treemap.PathFormat // how ids are joined into paths, ids with separator are escaped, error when format has no escape
+	Progress treemap.Progress   // no progress is reported when not set
+}
+
+type edge struct {
+	id     string
+	parent string
+	size   float64
+	label  string
+}
+
+// ParseReader parses edge list from a reader into a tree structure
+func (s *EdgeListParser) ParseReader(reader io.Reader) (*treemap.Tree, error) {
+	return s.ParseReaderContext(context.Background(), reader)
+}
+
+// ParseReaderContext is same as ParseReader, but stops with context error when context is done.
+func (s *EdgeListParser) ParseReaderContext(ctx context.Context, reader io.Reader) (*treemap.Tree, error) {
+	edges, err := s.readEdges(ctx, reader)
+	if err != nil {
+		return nil, err
+	}
+	return s.makeTree(edges)
+}
+
+// ParseFile parses edge list file into a tree structure
+func (s *EdgeListParser) ParseFile(filepath string) (*treemap.Tree, error) {
+	return s.ParseFileContext(context.Background(), filepath)
+}
+
+// ParseFileContext is same as ParseFile, but stops with context error when context is done.
+func (s *EdgeListParser) ParseFileContext(ctx context.Context, filepath string) (*treemap.Tree, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open file: %w", err)
+	}
+	defer file.Close()
+
+	return s.ParseReaderContext(ctx, file)
+}
+
+func (s *EdgeListParser) readEdges(ctx context.Context, reader io.Reader) ([]edge, error) {
+	r := newCSVReader(reader, s.Comma)
+
+	bar := treemap.ProgressOrNop(s.Progress)
+	bar.Start("Parsing edge list records", -1)
+	defer bar.Finish()
+
+	var edges []edge
+	for row := 1; ; row++ {
+		if row%checkContextEvery == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading CSV: %w", err)
+		}
+
+		if row == 1 && len(record) > 0 && record[0] == "id" {
+			// skip header
+			continue
+		}
+
+		if len(record) < 2 {
+			return nil, fmt.Errorf("row(%d) has no parent id", row)
+		}
+		if record[0] == "" {
+			return nil, fmt.Errorf("row(%d) has empty id", row)
+		}
+
+		e := edge{id: record[0], parent: record[1]}
+		if len(record) >= 3 && record[2] != "" {
+			if e.size, err = strconv.ParseFloat(record[2], 64); err != nil {
+				return nil, fmt.Errorf("size(%s) is not float: %w", record[2], err)
+			}
+		}
+		if len(record) >= 4 {
+			e.label = record[3]
+		}
+
+		edges = append(edges, e)
+		bar.Add(1)
+	}
+
+	return edges, nil
+}
+
+// makeTree checks that edges make forest and joins ids into paths from roots.
+func (s *EdgeListParser) makeTree(edges []edge) (*treemap.Tree, error) {
+	byID := make(map[string]int, len(edges))
+	for i, e := range edges {
+		if _, ok := byID[e.id]; ok {
+			return nil, fmt.Errorf("duplicate id(%s)", e.id)
+		}
+		// without escape, separator in id would make levels that are not in edges
+		if s.Format.Escape == 0 && len(s.Format.Split(e.id)) > 1 {
+			return nil, fmt.Errorf("id(%s) contains separator of paths, set escape of format", e.id)
+		}
+		byID[e.id] = i
+	}
+
+	var roots []int
+	children := make(map[string][]int)
+	for i, e := range edges {
+		if e.parent == "" {
+			roots = append(roots, i)
+			continue
+		}
+		if _, ok := byID[e.parent]; !ok {
+			return nil, fmt.Errorf("parent(%s) of node(%s) not found", e.parent, e.id)
+		}
+		children[e.parent] = append(children[e.parent], i)
+	}
+
+	tree := &treemap.Tree{
+		Nodes:  make(map[string]treemap.Node, len(edges)),
+		To:     make(map[string][]string),
+		Format: s.Format,
+	}
+
+	// paths from roots, nodes in cycles are not reachable from roots
+	paths := make([]string, len(edges))
+	que := make([]int, 0, len(edges))
+	for _, i := range roots {
+		paths[i] = s.Format.EscapePart(edges[i].id)
+		que = append(que, i)
+	}
+	for len(que) > 0 {
+		i := que[0]
+		que = que[1:]
+
+		e := edges[i]
+		name := e.label
+		if name == "" {
+			name = e.id
+		}
+		if _, ok := tree.Nodes[paths[i]]; ok {
+			return nil, fmt.Errorf("path(%s) of node(%s) is path of other node", paths[i], e.id)
+		}
+		tree.Nodes[paths[i]] = treemap.Node{Path: paths[i], Name: s.Format.EscapePart(name), Size: e.size}
+
+		for _, child := range children[e.id] {
+			paths[child] = s.Format.Join(paths[i], s.Format.EscapePart(edges[child].id))
+			tree.To[paths[i]] = append(tree.To[paths[i]], paths[child])
+			que = append(que, child)
+		}
+	}
+
+	if len(tree.Nodes) < len(edges) {
+		for i := range edges {
+			if paths[i] == "" {
+				return nil, cycleError(edges, byID, i)
+			}
+		}
+	}
+
+	switch {
+	case len(roots) == 0:
+		return nil, errors.New("no roots, empty input")
+	case len(roots) > 1:
+		tree.Root = "some-secret-string"
+		for _, i := range roots {
+			tree.To[tree.Root] = append(tree.To[tree.Root], paths[i])
+		}
+	default:
+		tree.Root = paths[roots[0]]
+	}
+
+	return tree, nil
+}
+
+// cycleError follows parents from node until some node repeats, such as "cycle in nodes: a -> b -> a"
+func cycleError(edges []edge, byID map[string]int, node int) error {
+	seen := make(map[int]int)
+	var ids []string
+	for q := node; ; q = byID[edges[q].parent] {
+		if start, ok := seen[q]; ok {
+			ids = append(ids[start:], edges[q].id)
+			break
+		}
+		seen[q] = len(ids)
+		ids = append(ids, edges[q].id)
+	}
+	return fmt.Errorf("cycle in nodes: %s", strings.Join(ids, " -> "))
+}
@@ -0,0 +1,149 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/MazenAlkhatib/treemap"
+)
+
+func TestEdgeListParser(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		expRoot  string
+		expTo    map[string][]string
+		expNodes map[string]treemap.Node
+		expErr   string
+	}{
+		{
+			name:    "when single root, then paths from root",
+			in:      "id,parent_id,size,label\nceo,,1,Chief\ncto,ceo,2\nalice,cto,3,Alice\nbob,ceo\n",
+			expRoot: "ceo",
+			expTo: map[string][]string{
+				"ceo":     {"ceo/cto", "ceo/bob"},
+				"ceo/cto": {"ceo/cto/alice"},
+			},
+			expNodes: map[string]treemap.Node{
+				"ceo":           {Path: "ceo", Name: "Chief", Size: 1},
+				"ceo/cto":       {Path: "ceo/cto", Name: "cto", Size: 2},
+				"ceo/cto/alice": {Path: "ceo/cto/alice", Name: "Alice", Size: 3},
+				"ceo/bob":       {Path: "ceo/bob", Name: "bob"},
+			},
+		},
+		{
+			name:    "when children before parents, then works",
+			in:      "b,a,1\na,,\n",
+			expRoot: "a",
+			expTo:   map[string][]string{"a": {"a/b"}},
+		},
+		{
+			name:    "when multiple roots, then fake root",
+			in:      "a,,1\nb,,2\nc,b,3\n",
+			expRoot: "some-secret-string",
+			expTo: map[string][]string{
+				"some-secret-string": {"a", "b"},
+				"b":                  {"b/c"},
+			},
+		},
+		{
+			name:   "when cycle, then error",
+			in:     "r,,1\na,c\nb,a\nc,b\n",
+			expErr: "cycle in nodes: a -> c -> b -> a",
+		},
+		{
+			name:   "when node is own parent, then error",
+			in:     "a,a\n",
+			expErr: "cycle in nodes: a -> a",
+		},
+		{
+			name:   "when orphan parent, then error",
+			in:     "a,,1\nb,x,1\n",
+			expErr: "parent(x) of node(b) not found",
+		},
+		{
+			name:   "when duplicate id, then error",
+			in:     "a,,1\na,,2\n",
+			expErr: "duplicate id(a)",
+		},
+		{
+			name:   "when id contains separator without escape, then error",
+			in:     "ns/pod,,1\nns/pod-rs,ns/pod,2\n",
+			expErr: "id(ns/pod) contains separator of paths",
+		},
+		{
+			name:   "when root id is path of child without escape, then error",
+			in:     "a,,1\na/b,,2\nb,a,3\n",
+			expErr: "id(a/b) contains separator of paths",
+		},
+		{
+			name:   "when wrong size, then error",
+			in:     "a,,x\n",
+			expErr: "size(x) is not float",
+		},
+		{
+			name:   "when empty, then error",
+			in:     "",
+			expErr: "no roots",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tree, err := (&EdgeListParser{}).ParseReader(strings.NewReader(tc.in))
+			assertError(t, err, tc.expErr)
+			if err != nil {
+				return
+			}
+
+			if tree.Root != tc.expRoot {
+				t.Errorf("root: exp(%s) != got(%s)", tc.expRoot, tree.Root)
+			}
+			if !reflect.DeepEqual(tc.expTo, tree.To) {
+				t.Errorf("edges: exp(%#v) != got(%#v)", tc.expTo, tree.To)
+			}
+			for path, exp := range tc.expNodes {
+				if got := tree.Nodes[path]; got != exp {
+					t.Errorf("%s: exp(%#v) != got(%#v)", path, exp, got)
+				}
+			}
+		})
+	}
+}
+
+func TestEdgeListParserEscapesIDs(t *testing.T) {
+	parser := EdgeListParser{Format: treemap.PathFormat{Escape: '\\'}}
+	tree, err := parser.ParseReader(strings.NewReader("ns/a,,1\npod/b,ns/a,2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp, got := []string{`ns\/a/pod\/b`}, tree.To[`ns\/a`]; !reflect.DeepEqual(exp, got) {
+		t.Errorf("exp(%v) != got(%v)", exp, got)
+	}
+	if parent, _ := tree.Format.Parent(`ns\/a/pod\/b`); parent != `ns\/a` {
+		t.Errorf("parent: exp(%s) != got(%s)", `ns\/a`, parent)
+	}
+}
+
+func TestEdgeListParserEscapedRootAndChild(t *testing.T) {
+	parser := EdgeListParser{Format: treemap.PathFormat{Escape: '\\'}}
+	tree, err := parser.ParseReader(strings.NewReader("a,,1\na/b,,2\nb,a,3\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expTo := map[string][]string{
+		"some-secret-string": {"a", `a\/b`},
+		"a":                  {"a/b"},
+	}
+	if !reflect.DeepEqual(expTo, tree.To) {
+		t.Errorf("edges: exp(%#v) != got(%#v)", expTo, tree.To)
+	}
+	for path, exp := range map[string]float64{"a": 1, `a\/b`: 2, "a/b": 3} {
+		if got := tree.Nodes[path].Size; got != exp {
+			t.Errorf("%s: exp(%v) != got(%v)", path, exp, got)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// ignoreRule is single pattern in format of .gitignore, such as "*.log", "/build/", "!keep.log" or "docs/**/*.md"
+type ignoreRule struct {
+	dir     string // directory of .gitignore relative to root, pattern matches only in its subtree, "." for root
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// parseIgnoreRules parses lines of .gitignore in directory.
+// Blank lines and comments are skipped, patterns with separator not at end are relative to directory.
+func parseIgnoreRules(dir string, r io.Reader) ([]ignoreRule, error) {
+	var rules []ignoreRule
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimRight(s.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule, err := parseIgnoreRule(dir, line)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read ignore rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+func parseIgnoreRule(dir, pattern string) (ignoreRule, error) {
+	rule := ignoreRule{dir: dir}
+
+	switch {
+	case strings.HasPrefix(pattern, `\#`), strings.HasPrefix(pattern, `\!`):
+		pattern = pattern[1:]
+	case strings.HasPrefix(pattern, "!"):
+		rule.negate = true
+		pattern = pattern[1:]
+	}
+
+	if strings.HasSuffix(pattern, "/") {
+		rule.dirOnly = true
+		pattern = strings.TrimRight(pattern, "/")
+	}
+
+	// patterns without separator match at any depth
+	prefix := "^(?:.*/)?"
+	if strings.Contains(pattern, "/") {
+		prefix = "^"
+		pattern = strings.TrimPrefix(pattern, "/")
+	}
+
+	re, err := regexp.Compile(prefix + ignoreGlobToRegexp(pattern) + "$")
+	if err != nil {
+		return ignoreRule{}, fmt.Errorf("invalid ignore pattern(%s): %w", pattern, err)
+	}
+	rule.re = re
+
+	return rule, nil
+}
+
+// ignoreGlobToRegexp translates glob where "**" matches any number of directories and "*" matches within one part
+func ignoreGlobToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/") && (i == 0 || glob[i-1] == '/'):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(regexp.QuoteMeta(glob[i:]))
+				return b.String()
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		default:
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	return b.String()
+}
+
+// isIgnored is true when last rule matching slash-separated name relative to root is not negated
+func isIgnored(rules []ignoreRule, name string, isDir bool) bool {
+	ignored := false
+	for _, rule := range rules {
+		rel := name
+		if rule.dir != "." {
+			var ok bool
+			if rel, ok = strings.CutPrefix(name, rule.dir+"/"); !ok {
+				continue
+			}
+		}
+
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.re.MatchString(rel) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
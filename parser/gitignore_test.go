@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsIgnored(t *testing.T) {
+	tests := []struct {
+		name  string
+		dir   string
+		rules string
+		path  string
+		isDir bool
+		exp   bool
+	}{
+		{name: "name at any depth", rules: "*.log", path: "a/b/c.log", exp: true},
+		{name: "name not matching", rules: "*.log", path: "a/b/c.go", exp: false},
+		{name: "star within part", rules: "a/*.log", path: "a/b/c.log", exp: false},
+		{name: "anchored", rules: "/build", path: "build", exp: true},
+		{name: "anchored not at root", rules: "/build", path: "a/build", exp: false},
+		{name: "dir only on dir", rules: "node_modules/", path: "a/node_modules", isDir: true, exp: true},
+		{name: "dir only on file", rules: "node_modules/", path: "a/node_modules", exp: false},
+		{name: "double star prefix", rules: "**/testdata", path: "a/b/testdata", isDir: true, exp: true},
+		{name: "double star middle", rules: "docs/**/*.md", path: "docs/a/b/c.md", exp: true},
+		{name: "double star middle no dirs", rules: "docs/**/*.md", path: "docs/c.md", exp: true},
+		{name: "double star suffix", rules: "docs/**", path: "docs/a/b", exp: true},
+		{name: "negation", rules: "*.log\n!keep.log", path: "a/keep.log", exp: false},
+		{name: "negation then ignore", rules: "!keep.log\n*.log", path: "a/keep.log", exp: true},
+		{name: "comment and blank", rules: "# *.go\n\n", path: "a.go", exp: false},
+		{name: "escaped hash", rules: `\#a`, path: "#a", exp: true},
+		{name: "question mark", rules: "a?.go", path: "ab.go", exp: true},
+		{name: "class", rules: "[!a]b", path: "cb", exp: true},
+		{name: "class not matching", rules: "[!a]b", path: "ab", exp: false},
+		{name: "nested gitignore", dir: "a", rules: "/b", path: "a/b", exp: true},
+		{name: "nested gitignore outside", dir: "a", rules: "b", path: "c/b", exp: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := tc.dir
+			if dir == "" {
+				dir = "."
+			}
+
+			rules, err := parseIgnoreRules(dir, strings.NewReader(tc.rules))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := isIgnored(rules, tc.path, tc.isDir); got != tc.exp {
+				t.Errorf("exp(%v) != got(%v)", tc.exp, got)
+			}
+		})
+	}
+}
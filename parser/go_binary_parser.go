@@ -0,0 +1,364 @@
+package parser
+
+import (
+	"context"
+	"debug/elf"
+	"debug/gosym"
+	"debug/macho"
+	"debug/pe"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/MazenAlkhatib/treemap"
+)
+
+// GoBinaryFormat is path format of trees of Go binaries.
+// Parts of symbols can contain "/", such as in type parameters, so it is escaped.
+var GoBinaryFormat = treemap.PathFormat{Separator: "/", Escape: '\\'}
+
+// GoBinaryParser reads sizes of symbols in ELF, Mach-O or PE executable built by Go into a tree structure.
+// Symbols are split into package path and names of receiver and function, such as
+// "github.com/foo/bar.(*T).Method" into "github.com/foo/bar/(*T)/Method", closures stay with their function.
+// Type descriptors and other symbols of linker are under group, such as "type:" or "go:itab", and C symbols are under "C".
+// Space in sections not covered by symbols is node named by section, such as ".gopclntab".
+// Symbols of Mach-O and PE have no sizes, so they are sized up to next symbol.
+// Stripped binaries have functions read from table of Go runtime, without data.
+type GoBinaryParser struct {
+	Progress treemap.Progress // no progress is reported when not set
+}
+
+// binarySection is section of executable with its contents in file, size is zero for sections without contents
+type binarySection struct {
+	name       string
+	addr, size uint64
+}
+
+// binarySymbol is symbol in section, size is zero when format does not have sizes
+type binarySymbol struct {
+	name       string
+	section    int
+	addr, size uint64
+}
+
+// binaryImage is sections and symbols of executable, with table of Go functions when symbols are stripped
+type binaryImage struct {
+	sections []binarySection
+	symbols  []binarySymbol
+	hasSizes bool
+
+	pclntab  []byte
+	text     int // index of section with code
+	textAddr uint64
+}
+
+// ParseFile parses executable file into a tree structure
+func (s *GoBinaryParser) ParseFile(filepath string) (*treemap.Tree, error) {
+	return s.ParseFileContext(context.Background(), filepath)
+}
+
+// ParseFileContext is same as ParseFile, but stops with context error when context is done.
+func (s *GoBinaryParser) ParseFileContext(ctx context.Context, filepath string) (*treemap.Tree, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open file: %w", err)
+	}
+	defer file.Close()
+
+	return s.ParseReaderAtContext(ctx, file)
+}
+
+// ParseReaderAt parses executable into a tree structure
+func (s *GoBinaryParser) ParseReaderAt(r io.ReaderAt) (*treemap.Tree, error) {
+	return s.ParseReaderAtContext(context.Background(), r)
+}
+
+// ParseReaderAtContext is same as ParseReaderAt, but stops with context error when context is done.
+func (s *GoBinaryParser) ParseReaderAtContext(ctx context.Context, r io.ReaderAt) (*treemap.Tree, error) {
+	img, err := readBinaryImage(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(img.symbols) == 0 {
+		if err := img.readGoFuncs(); err != nil {
+			return nil, err
+		}
+	}
+	if !img.hasSizes {
+		img.fillSizes()
+	}
+
+	b := newTreeBuilder()
+	b.setNames = true
+	b.tree.Format = GoBinaryFormat
+
+	bar := treemap.ProgressOrNop(s.Progress)
+	bar.Start("Reading symbols", int64(len(img.symbols)))
+	defer bar.Finish()
+
+	covered := make([]uint64, len(img.sections))
+	for i, sym := range img.symbols {
+		if i%checkContextEvery == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		bar.Add(1)
+
+		section := img.sections[sym.section]
+		if sym.size == 0 || section.size == 0 || sym.addr < section.addr || sym.addr >= section.addr+section.size {
+			continue
+		}
+		size := min(sym.size, section.addr+section.size-sym.addr)
+		covered[sym.section] += size
+
+		b.add(treemap.Node{Path: goSymbolPath(sym.name), Size: float64(size)})
+	}
+
+	for i, section := range img.sections {
+		if section.name != "" && section.size > covered[i] {
+			b.add(treemap.Node{Path: GoBinaryFormat.EscapePart(section.name), Size: float64(section.size - covered[i])})
+		}
+	}
+
+	return b.build()
+}
+
+func readBinaryImage(r io.ReaderAt) (*binaryImage, error) {
+	if f, err := elf.NewFile(r); err == nil {
+		return readELF(f)
+	}
+	if f, err := macho.NewFile(r); err == nil {
+		return readMachO(f)
+	}
+	if f, err := pe.NewFile(r); err == nil {
+		return readPE(f)
+	}
+	return nil, errors.New("unknown format of executable, expected ELF, Mach-O or PE")
+}
+
+func readELF(f *elf.File) (*binaryImage, error) {
+	img := binaryImage{hasSizes: true, text: -1}
+	for i, s := range f.Sections {
+		section := binarySection{name: s.Name, addr: s.Addr}
+		// compressed sections, such as debug info, are smaller in file
+		if s.Type != elf.SHT_NOBITS && s.Type != elf.SHT_NULL {
+			section.size = s.FileSize
+		}
+		// sections not loaded into memory have no addresses, such as debug info
+		if s.Flags&elf.SHF_ALLOC == 0 {
+			section.addr = 0
+		}
+		img.sections = append(img.sections, section)
+
+		switch s.Name {
+		case ".text":
+			img.text, img.textAddr = i, s.Addr
+		case ".gopclntab":
+			data, err := s.Data()
+			if err != nil {
+				return nil, fmt.Errorf("cannot read section(%s): %w", s.Name, err)
+			}
+			img.pclntab = data
+		}
+	}
+
+	syms, err := f.Symbols()
+	if err != nil && !errors.Is(err, elf.ErrNoSymbols) {
+		return nil, fmt.Errorf("cannot read symbols: %w", err)
+	}
+	for _, sym := range syms {
+		if sym.Section == elf.SHN_UNDEF || sym.Section >= elf.SHN_LORESERVE || int(sym.Section) >= len(img.sections) {
+			continue
+		}
+		if t := elf.ST_TYPE(sym.Info); t == elf.STT_SECTION || t == elf.STT_FILE {
+			continue
+		}
+		img.symbols = append(img.symbols, binarySymbol{name: sym.Name, section: int(sym.Section), addr: sym.Value, size: sym.Size})
+	}
+
+	return &img, nil
+}
+
+func readMachO(f *macho.File) (*binaryImage, error) {
+	img := binaryImage{text: -1}
+	for i, s := range f.Sections {
+		section := binarySection{name: s.Name, addr: s.Addr, size: s.Size}
+		switch s.Flags & 0xff {
+		case 0x1, 0xc, 0x12: // zero fill sections have no contents
+			section.size = 0
+		}
+		img.sections = append(img.sections, section)
+
+		switch s.Name {
+		case "__text":
+			img.text, img.textAddr = i, s.Addr
+		case "__gopclntab":
+			data, err := s.Data()
+			if err != nil {
+				return nil, fmt.Errorf("cannot read section(%s): %w", s.Name, err)
+			}
+			img.pclntab = data
+		}
+	}
+
+	if f.Symtab != nil {
+		for _, sym := range f.Symtab.Syms {
+			// debugging symbols or symbols not in sections, sections are numbered from one
+			if sym.Type&0xe0 != 0 || sym.Sect == 0 || int(sym.Sect) > len(img.sections) {
+				continue
+			}
+			// names of symbols in Mach-O start with underscore
+			name := strings.TrimPrefix(sym.Name, "_")
+			img.symbols = append(img.symbols, binarySymbol{name: name, section: int(sym.Sect) - 1, addr: sym.Value})
+		}
+	}
+
+	return &img, nil
+}
+
+func readPE(f *pe.File) (*binaryImage, error) {
+	img := binaryImage{text: -1}
+	for i, s := range f.Sections {
+		// addresses of symbols are offsets in sections
+		img.sections = append(img.sections, binarySection{name: s.Name, size: uint64(s.Size)})
+		if s.Name == ".text" {
+			img.text = i
+		}
+	}
+
+	for _, sym := range f.Symbols {
+		// sections are numbered from one
+		if sym.SectionNumber <= 0 || int(sym.SectionNumber) > len(img.sections) {
+			continue
+		}
+		img.symbols = append(img.symbols, binarySymbol{name: sym.Name, section: int(sym.SectionNumber) - 1, addr: uint64(sym.Value)})
+	}
+
+	return &img, nil
+}
+
+// readGoFuncs reads functions from table of Go runtime, which is kept in stripped binaries
+func (img *binaryImage) readGoFuncs() error {
+	if img.pclntab == nil || img.text < 0 {
+		return errors.New("no symbols and no table of Go functions in executable")
+	}
+
+	table, err := gosym.NewTable(nil, gosym.NewLineTable(img.pclntab, img.textAddr))
+	if err != nil {
+		return fmt.Errorf("cannot read table of Go functions: %w", err)
+	}
+
+	for _, fn := range table.Funcs {
+		if fn.End > fn.Entry {
+			img.symbols = append(img.symbols, binarySymbol{name: fn.Name, section: img.text, addr: fn.Entry, size: fn.End - fn.Entry})
+		}
+	}
+	img.hasSizes = true
+
+	return nil
+}
+
+// fillSizes sets sizes of symbols by address of next symbol in same section or end of section
+func (img *binaryImage) fillSizes() {
+	sort.SliceStable(img.symbols, func(i, j int) bool {
+		a, b := img.symbols[i], img.symbols[j]
+		if a.section != b.section {
+			return a.section < b.section
+		}
+		return a.addr < b.addr
+	})
+
+	for i := range img.symbols {
+		sym := &img.symbols[i]
+		end := img.sections[sym.section].addr + img.sections[sym.section].size
+		if i+1 < len(img.symbols) && img.symbols[i+1].section == sym.section {
+			end = img.symbols[i+1].addr
+		}
+		if end > sym.addr {
+			sym.size = end - sym.addr
+		}
+	}
+}
+
+// linkerSymbolGroup matches groups of symbols made by linker, such as "type:", "go:itab" or "type." before Go 1.20
+var linkerSymbolGroup = regexp.MustCompile(`^(type:|go:[a-z]+|type\.|go\.[a-z]+\.)`)
+
+// closureName matches names of closures and wrappers, such as "func1", "gowrap2" or "3" of nested closure
+var closureName = regexp.MustCompile(`^(func|gowrap|deferwrap)?\d+$`)
+
+// goSymbolPath is path of symbol in GoBinaryFormat
+func goSymbolPath(name string) string {
+	parts := goSymbolParts(name)
+	for i := range parts {
+		parts[i] = GoBinaryFormat.EscapePart(parts[i])
+	}
+	return GoBinaryFormat.Join(parts...)
+}
+
+// goSymbolParts splits symbol into parts of package path and names in package
+func goSymbolParts(name string) []string {
+	if group := linkerSymbolGroup.FindString(name); group != "" {
+		rest := strings.TrimPrefix(name[len(group):], ".")
+		group = strings.TrimSuffix(group, ".")
+		if rest == "" {
+			return []string{group}
+		}
+		return []string{group, rest}
+	}
+
+	// package path ends at first dot after last slash, slashes in type parameters are not in package path
+	prefix := name
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		prefix = name[:i]
+	}
+	start := strings.LastIndexByte(prefix, '/') + 1
+	dot := strings.IndexByte(prefix[start:], '.')
+	if dot <= 0 {
+		return []string{"C", name}
+	}
+
+	pkg, rest := name[:start+dot], name[start+dot+1:]
+	parts := strings.Split(pkg, "/")
+
+	// names of compiler, such as "..stmp_0" or "..inittask", are not split
+	if strings.HasPrefix(rest, ".") {
+		return append(parts, rest)
+	}
+
+	var names []string
+	depth, last := 0, 0
+	for i := 0; i <= len(rest); i++ {
+		if i < len(rest) {
+			switch rest[i] {
+			case '[', '(':
+				depth++
+				continue
+			case ']', ')':
+				depth--
+				continue
+			case '.':
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+
+		part := rest[last:i]
+		last = i + 1
+		if len(names) > 0 && closureName.MatchString(part) {
+			names[len(names)-1] += "." + part
+			continue
+		}
+		names = append(names, part)
+	}
+
+	return append(parts, names...)
+}
@@ -0,0 +1,118 @@
+package parser
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestGoSymbolParts(t *testing.T) {
+	tests := []struct {
+		name string
+		exp  []string
+	}{
+		{name: "runtime.mallocgc", exp: []string{"runtime", "mallocgc"}},
+		{name: "main.main", exp: []string{"main", "main"}},
+		{name: "github.com/foo/bar.(*T).Method", exp: []string{"github.com", "foo", "bar", "(*T)", "Method"}},
+		{name: "gopkg.in/yaml.v2.Unmarshal", exp: []string{"gopkg.in", "yaml", "v2", "Unmarshal"}},
+		{name: "github.com/foo/bar.T.Method.func1.2", exp: []string{"github.com", "foo", "bar", "T", "Method.func1.2"}},
+		{name: "github.com/foo/bar.Run.gowrap1", exp: []string{"github.com", "foo", "bar", "Run.gowrap1"}},
+		{name: "slices.Sort[go.shape.[]github.com/foo/bar.T]", exp: []string{"slices", "Sort[go.shape.[]github.com/foo/bar.T]"}},
+		{name: "container/list.(*List[go.shape.int]).Push", exp: []string{"container", "list", "(*List[go.shape.int])", "Push"}},
+		{name: "net/http..stmp_0", exp: []string{"net", "http", ".stmp_0"}},
+		{name: "type:*github.com/foo/bar.T", exp: []string{"type:", "*github.com/foo/bar.T"}},
+		{name: "go:itab.*os.File,io.Writer", exp: []string{"go:itab", "*os.File,io.Writer"}},
+		{name: "go:buildinfo", exp: []string{"go:buildinfo"}},
+		{name: "type.*github.com/foo.T", exp: []string{"type", "*github.com/foo.T"}},
+		{name: "go.itab.*os.File,io.Writer", exp: []string{"go.itab", "*os.File,io.Writer"}},
+		{name: "_cgo_init", exp: []string{"C", "_cgo_init"}},
+		{name: ".L1", exp: []string{"C", ".L1"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := goSymbolParts(tc.name); !reflect.DeepEqual(tc.exp, got) {
+				t.Errorf("exp(%#v) != got(%#v)", tc.exp, got)
+			}
+		})
+	}
+}
+
+func TestGoSymbolPathEscapesSlashes(t *testing.T) {
+	exp := `slices/Sort[go.shape.*github.com\/foo.T]`
+	if got := goSymbolPath("slices.Sort[go.shape.*github.com/foo.T]"); got != exp {
+		t.Errorf("exp(%s) != got(%s)", exp, got)
+	}
+}
+
+func TestGoBinaryParserTestExecutable(t *testing.T) {
+	path, err := os.Executable()
+	if err != nil {
+		t.Skip(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tree, err := (&GoBinaryParser{}).ParseFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, exp := range []string{"runtime", "github.com/MazenAlkhatib/treemap/parser/TestGoBinaryParserTestExecutable"} {
+		if _, ok := tree.Nodes[exp]; !ok {
+			t.Errorf("exp node(%s)", exp)
+		}
+	}
+
+	// symbols and rest of sections are within file, headers are not in sections
+	var total float64
+	for _, node := range tree.Nodes {
+		if len(tree.To[node.Path]) == 0 {
+			total += node.Size
+		}
+	}
+	if size := float64(info.Size()); total > size || total < size/2 {
+		t.Errorf("exp(%v) ~ got(%v)", size, total)
+	}
+}
+
+func TestGoBinaryParserUnknownFormat(t *testing.T) {
+	_, err := (&GoBinaryParser{}).ParseReaderAt(bytes.NewReader([]byte("a/b,1\n")))
+	assertError(t, err, "unknown format of executable")
+}
+
+func TestGoBinaryParserStripped(t *testing.T) {
+	path, err := os.Executable()
+	if err != nil {
+		t.Skip(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	img, err := readBinaryImage(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.pclntab == nil {
+		t.Skip("no table of Go functions in section")
+	}
+
+	img.symbols = nil
+	if err := img.readGoFuncs(); err != nil {
+		t.Fatal(err)
+	}
+
+	found := false
+	for _, sym := range img.symbols {
+		found = found || sym.name == "github.com/MazenAlkhatib/treemap/parser.TestGoBinaryParserStripped"
+	}
+	if !found {
+		t.Errorf("exp function of test in (%d) symbols", len(img.symbols))
+	}
+}
@@ -0,0 +1,153 @@
+package parser
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/MazenAlkhatib/treemap"
+)
+
+// JSONLinesTreeParser parses JSON Lines of records with path, size and optional heat into a tree structure,
+// same as CSVTreeParser parses rows, such as:
+//
+//	{"path": "a/b", "size": 1, "heat": 2}
+type JSONLinesTreeParser struct {
+	Format   treemap.PathFormat // how paths are split into parts, "/" without escaping when not set
+	Progress treemap.Progress   // no progress is reported when not set
+}
+
+type jsonRecord struct {
+	Path string   `json:"path"`
+	Size float64  `json:"size"`
+	Heat *float64 `json:"heat"`
+}
+
+// ParseReader parses JSON Lines from a reader into a tree structure
+func (s *JSONLinesTreeParser) ParseReader(reader io.Reader) (*treemap.Tree, error) {
+	return s.ParseReaderContext(context.Background(), reader)
+}
+
+// ParseReaderContext is same as ParseReader, but stops with context error when context is done.
+func (s *JSONLinesTreeParser) ParseReaderContext(ctx context.Context, reader io.Reader) (*treemap.Tree, error) {
+	b := newTreeBuilder()
+	b.setNames = true
+	b.tree.Format = s.Format
+
+	if err := s.readNodes(ctx, reader, b.add); err != nil {
+		return nil, err
+	}
+
+	return b.build()
+}
+
+// ParseReaderCompact parses JSON Lines from a reader into a compact tree structure, for large inputs
+func (s *JSONLinesTreeParser) ParseReaderCompact(reader io.Reader) (*treemap.CompactTree, error) {
+	return s.ParseReaderCompactContext(context.Background(), reader)
+}
+
+// ParseReaderCompactContext is same as ParseReaderCompact, but stops with context error when context is done.
+func (s *JSONLinesTreeParser) ParseReaderCompactContext(ctx context.Context, reader io.Reader) (*treemap.CompactTree, error) {
+	b := treemap.NewCompactTreeBuilder()
+	b.Format = s.Format
+
+	if err := s.readNodes(ctx, reader, b.Add); err != nil {
+		return nil, err
+	}
+
+	tree := b.Build()
+	if len(tree.Nodes) == 1 {
+		return nil, errors.New("no roots, empty input")
+	}
+
+	return tree, nil
+}
+
+// readNodes decodes records and passes nodes to add one by one
+func (s *JSONLinesTreeParser) readNodes(ctx context.Context, reader io.Reader, add func(node treemap.Node)) error {
+	d := json.NewDecoder(skipByteOrderMark(reader))
+
+	bar := treemap.ProgressOrNop(s.Progress)
+	bar.Start("Parsing JSON Lines records", -1)
+	defer bar.Finish()
+
+	for line := 1; ; line++ {
+		if line%checkContextEvery == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+
+		var record jsonRecord
+		if err := d.Decode(&record); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("error reading JSON in record(%d): %w", line, err)
+		}
+
+		if record.Path == "" {
+			return fmt.Errorf("no path in record(%d)", line)
+		}
+		if s.Format.HasTrailingSeparator(record.Path) {
+			continue
+		}
+
+		node := treemap.Node{Path: record.Path, Size: record.Size}
+		if record.Heat != nil {
+			node.Heat = *record.Heat
+			node.HasHeat = true
+		}
+
+		add(node)
+		bar.Add(1)
+	}
+
+	return nil
+}
+
+// skipByteOrderMark skips UTF-8 byte order mark, which JSON decoder does not accept, but editors on Windows write
+func skipByteOrderMark(reader io.Reader) io.Reader {
+	r := bufio.NewReader(reader)
+	if head, _ := r.Peek(len(byteOrderMark)); string(head) == byteOrderMark {
+		r.Discard(len(byteOrderMark))
+	}
+	return r
+}
+
+const byteOrderMark = "\ufeff"
+
+// ParseFile parses JSON Lines file into a tree structure
+func (s *JSONLinesTreeParser) ParseFile(filepath string) (*treemap.Tree, error) {
+	return s.ParseFileContext(context.Background(), filepath)
+}
+
+// ParseFileContext is same as ParseFile, but stops with context error when context is done.
+func (s *JSONLinesTreeParser) ParseFileContext(ctx context.Context, filepath string) (*treemap.Tree, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open file: %w", err)
+	}
+	defer file.Close()
+
+	return s.ParseReaderContext(ctx, file)
+}
+
+// ParseFileCompact parses JSON Lines file into a compact tree structure
+func (s *JSONLinesTreeParser) ParseFileCompact(filepath string) (*treemap.CompactTree, error) {
+	return s.ParseFileCompactContext(context.Background(), filepath)
+}
+
+// ParseFileCompactContext is same as ParseFileCompact, but stops with context error when context is done.
+func (s *JSONLinesTreeParser) ParseFileCompactContext(ctx context.Context, filepath string) (*treemap.CompactTree, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open file: %w", err)
+	}
+	defer file.Close()
+
+	return s.ParseReaderCompactContext(ctx, file)
+}
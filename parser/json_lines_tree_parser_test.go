@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJSONLinesTreeParser(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		csv    string
+		expErr string
+	}{
+		{
+			name: "when records, then same as csv",
+			in:   `{"path": "a/b/c", "size": 1, "heat": 2}` + "\n" + `{"path": "a/b/d", "size": 3}` + "\n" + `{"path": "a/e", "size": 4, "heat": 5}` + "\n",
+			csv:  "a/b/c,1,2\na/b/d,3\na/e,4,5\n",
+		},
+		{
+			name: "when duplicates, then merged",
+			in:   `{"path": "a/b", "size": 1, "heat": 2}` + "\n" + `{"path": "a/b", "size": 3, "heat": 4}`,
+			csv:  "a/b,1,2\na/b,3,4\n",
+		},
+		{
+			name: "when byte order mark, then skipped",
+			in:   "\ufeff" + `{"path": "a/b", "size": 1}` + "\n",
+			csv:  "a/b,1\n",
+		},
+		{
+			name: "when multiple roots, then fake root",
+			in:   `{"path": "a", "size": 1}{"path": "b", "size": 2}`,
+			csv:  "a,1\nb,2\n",
+		},
+		{
+			name:   "when no path, then error",
+			in:     `{"size": 1}`,
+			expErr: "no path in record(1)",
+		},
+		{
+			name:   "when size is not number, then error",
+			in:     `{"path": "a", "size": 1}` + "\n" + `{"path": "b", "size": "x"}`,
+			expErr: "error reading JSON in record(2)",
+		},
+		{
+			name:   "when empty, then error",
+			in:     "",
+			expErr: "no roots",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tree, err := (&JSONLinesTreeParser{}).ParseReader(strings.NewReader(tc.in))
+			assertError(t, err, tc.expErr)
+			if err != nil {
+				return
+			}
+
+			expTree, err := (&CSVTreeParser{}).ParseReader(strings.NewReader(tc.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !eqTree(*expTree, *tree) {
+				t.Errorf("tree: exp(%#v) != got(%#v)", expTree, tree)
+			}
+		})
+	}
+}
+
+func TestJSONLinesTreeParserCompact(t *testing.T) {
+	in := `{"path": "a/b/c", "size": 1, "heat": 2}` + "\n" + `{"path": "a/e", "size": 4}` + "\n"
+
+	parser := JSONLinesTreeParser{}
+	expTree, err := parser.ParseReader(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compact, err := parser.ParseReaderCompact(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tree := compact.Tree(); !eqTree(*expTree, *tree) {
+		t.Errorf("tree: exp(%#v) != got(%#v)", expTree, tree)
+	}
+}
@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/MazenAlkhatib/treemap"
+)
+
+// JSONTreeParser parses nested JSON objects into a tree structure, in shape used by d3-hierarchy:
+//
+//	{"name": "a", "children": [{"name": "b", "size": 1, "heat": 2}, {"name": "c", "value": 3}]}
+//
+// Paths are joined names of ancestors, such as "a/b". Size is read from "size" or "value".
+// Siblings with same name are merged as duplicate rows in CSV.
+type JSONTreeParser struct {
+	Format   treemap.PathFormat // how names are joined into paths, names with separator are escaped, error when format has no escape
+	Progress treemap.Progress   // no progress is reported when not set
+}
+
+type jsonNode struct {
+	Name     string     `json:"name"`
+	Size     *float64   `json:"size"`
+	Value    *float64   `json:"value"`
+	Heat     *float64   `json:"heat"`
+	Children []jsonNode `json:"children"`
+}
+
+// ParseReader parses JSON from a reader into a tree structure
+func (s *JSONTreeParser) ParseReader(reader io.Reader) (*treemap.Tree, error) {
+	return s.ParseReaderContext(context.Background(), reader)
+}
+
+// ParseReaderContext is same as ParseReader, but stops with context error when context is done.
+func (s *JSONTreeParser) ParseReaderContext(ctx context.Context, reader io.Reader) (*treemap.Tree, error) {
+	var root jsonNode
+	if err := json.NewDecoder(skipByteOrderMark(reader)).Decode(&root); err != nil {
+		if err == io.EOF {
+			return nil, errors.New("no roots, empty input")
+		}
+		return nil, fmt.Errorf("error reading JSON: %w", err)
+	}
+
+	b := newTreeBuilder()
+	b.setNames = true
+	b.tree.Format = s.Format
+
+	bar := treemap.ProgressOrNop(s.Progress)
+	bar.Start("Parsing JSON nodes", -1)
+	defer bar.Finish()
+
+	count := 0
+	var add func(parent string, n jsonNode) error
+	add = func(parent string, n jsonNode) error {
+		if count%checkContextEvery == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		count++
+
+		if n.Name == "" {
+			if parent == "" {
+				return errors.New("root has no name")
+			}
+			return fmt.Errorf("child of node(%s) has no name", parent)
+		}
+
+		// without escape, separator in name would make levels that are not in input
+		if s.Format.Escape == 0 && len(s.Format.Split(n.Name)) > 1 {
+			return fmt.Errorf("name(%s) contains separator of paths, set escape of format", n.Name)
+		}
+
+		node := treemap.Node{Path: s.Format.EscapePart(n.Name)}
+		if parent != "" {
+			node.Path = s.Format.Join(parent, node.Path)
+		}
+		switch {
+		case n.Size != nil:
+			node.Size = *n.Size
+		case n.Value != nil:
+			node.Size = *n.Value
+		}
+		if n.Heat != nil {
+			node.Heat = *n.Heat
+			node.HasHeat = true
+		}
+
+		b.add(node)
+		bar.Add(1)
+
+		for _, child := range n.Children {
+			if err := add(node.Path, child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := add("", root); err != nil {
+		return nil, err
+	}
+
+	return b.build()
+}
+
+// ParseFile parses JSON file into a tree structure
+func (s *JSONTreeParser) ParseFile(filepath string) (*treemap.Tree, error) {
+	return s.ParseFileContext(context.Background(), filepath)
+}
+
+// ParseFileContext is same as ParseFile, but stops with context error when context is done.
+func (s *JSONTreeParser) ParseFileContext(ctx context.Context, filepath string) (*treemap.Tree, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open file: %w", err)
+	}
+	defer file.Close()
+
+	return s.ParseReaderContext(ctx, file)
+}
@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MazenAlkhatib/treemap"
+)
+
+func TestJSONTreeParser(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		csv    string
+		expErr string
+	}{
+		{
+			name: "when nested, then same as csv",
+			in: `{"name": "a", "children": [
+				{"name": "b", "children": [{"name": "c", "size": 1, "heat": 2}, {"name": "d", "value": 3}]},
+				{"name": "e", "size": 4, "heat": 5}
+			]}`,
+			csv: "a,0\na/b,0\na/b/c,1,2\na/b/d,3\na/e,4,5\n",
+		},
+		{
+			name: "when byte order mark, then skipped",
+			in:   "\ufeff" + `{"name": "a", "children": [{"name": "b", "size": 1}]}`,
+			csv:  "a,0\na/b,1\n",
+		},
+		{
+			name: "when parent has size, then size is kept",
+			in:   `{"name": "a", "size": 10, "children": [{"name": "b", "size": 1}]}`,
+			csv:  "a,10\na/b,1\n",
+		},
+		{
+			name: "when duplicate siblings, then merged",
+			in:   `{"name": "a", "children": [{"name": "b", "size": 1}, {"name": "b", "size": 2}]}`,
+			csv:  "a,0\na/b,3\n",
+		},
+		{
+			name:   "when child has no name, then error",
+			in:     `{"name": "a", "children": [{"size": 1}]}`,
+			expErr: "child of node(a) has no name",
+		},
+		{
+			name:   "when root has no name, then error",
+			in:     `{"size": 1}`,
+			expErr: "root has no name",
+		},
+		{
+			name:   "when name contains separator without escape, then error",
+			in:     `{"name": "a", "children": [{"name": "github.com/foo", "size": 1}]}`,
+			expErr: "name(github.com/foo) contains separator of paths",
+		},
+		{
+			name:   "when not object, then error",
+			in:     `[1, 2]`,
+			expErr: "error reading JSON",
+		},
+		{
+			name:   "when empty, then error",
+			in:     "",
+			expErr: "no roots",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tree, err := (&JSONTreeParser{}).ParseReader(strings.NewReader(tc.in))
+			assertError(t, err, tc.expErr)
+			if err != nil {
+				return
+			}
+
+			expTree, err := (&CSVTreeParser{}).ParseReader(strings.NewReader(tc.csv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !eqTree(*expTree, *tree) {
+				t.Errorf("tree: exp(%#v) != got(%#v)", expTree, tree)
+			}
+		})
+	}
+}
+
+func TestJSONTreeParserEscapesNames(t *testing.T) {
+	parser := JSONTreeParser{Format: treemap.PathFormat{Escape: '\\'}}
+	tree, err := parser.ParseReader(strings.NewReader(`{"name": "a/b", "children": [{"name": "c", "size": 1}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := `a\/b/c`; tree.Nodes[exp].Name != "c" {
+		t.Errorf("exp(%s) in (%#v)", exp, tree.Nodes)
+	}
+}
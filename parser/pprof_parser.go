@@ -0,0 +1,199 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/MazenAlkhatib/treemap"
+)
+
+// PprofParser parses pprof profile, gzipped or not, into a tree structure of call stacks or of functions.
+// Tree of call stacks has frames from root to leaf, such as "runtime.main/main.main/main.work",
+// nodes are sized by values of samples of stacks ending in them, and their size within parent is in "(self)" child.
+// Tree of functions is split by package paths as for GoBinaryParser and sized by values of samples in functions.
+// Paths are in GoBinaryFormat.
+type PprofParser struct {
+	SampleType string // such as cpu, samples, alloc_space or inuse_space, default sample type of profile when not set
+	ByFunction bool   // tree of package paths and functions instead of call stacks
+	Progress   treemap.Progress
+}
+
+// pprofSelf is name of node with value of function itself when it has callees
+const pprofSelf = "(self)"
+
+// ParseReader parses pprof profile from a reader into a tree structure
+func (s *PprofParser) ParseReader(reader io.Reader) (*treemap.Tree, error) {
+	return s.ParseReaderContext(context.Background(), reader)
+}
+
+// ParseReaderContext is same as ParseReader, but stops with context error when context is done.
+func (s *PprofParser) ParseReaderContext(ctx context.Context, reader io.Reader) (*treemap.Tree, error) {
+	data, err := readPprofData(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := decodePprofProfile(data)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := s.sampleTypeIndex(p)
+	if err != nil {
+		return nil, err
+	}
+
+	bar := treemap.ProgressOrNop(s.Progress)
+	bar.Start("Reading samples", int64(len(p.samples)))
+	defer bar.Finish()
+
+	// sizes by paths, in order of first sample
+	var paths []string
+	sizes := make(map[string]float64)
+	for i, sample := range p.samples {
+		if i%checkContextEvery == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		bar.Add(1)
+
+		if value >= len(sample.values) || sample.values[value] == 0 {
+			continue
+		}
+
+		frames := p.frames(sample)
+		if len(frames) == 0 {
+			continue
+		}
+
+		var path string
+		if s.ByFunction {
+			path = goSymbolPath(frames[len(frames)-1])
+		} else {
+			for i := range frames {
+				frames[i] = GoBinaryFormat.EscapePart(frames[i])
+			}
+			path = GoBinaryFormat.Join(frames...)
+		}
+
+		if _, ok := sizes[path]; !ok {
+			paths = append(paths, path)
+		}
+		sizes[path] += float64(sample.values[value])
+	}
+	if len(paths) == 0 {
+		return nil, errors.New("no roots, no samples with values")
+	}
+
+	// values of nodes with children are moved to child, so that parents are sums of children
+	parents := make(map[string]bool)
+	for _, path := range paths {
+		for parent, ok := GoBinaryFormat.Parent(path); ok && !parents[parent]; parent, ok = GoBinaryFormat.Parent(parent) {
+			parents[parent] = true
+		}
+	}
+
+	b := newTreeBuilder()
+	b.setNames = true
+	b.tree.Format = GoBinaryFormat
+	for _, path := range paths {
+		node := treemap.Node{Path: path, Size: sizes[path]}
+		if parents[path] {
+			node.Path = GoBinaryFormat.Join(path, GoBinaryFormat.EscapePart(pprofSelf))
+		}
+		b.add(node)
+	}
+
+	return b.build()
+}
+
+// ParseFile parses pprof profile file into a tree structure
+func (s *PprofParser) ParseFile(filepath string) (*treemap.Tree, error) {
+	return s.ParseFileContext(context.Background(), filepath)
+}
+
+// ParseFileContext is same as ParseFile, but stops with context error when context is done.
+func (s *PprofParser) ParseFileContext(ctx context.Context, filepath string) (*treemap.Tree, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open file: %w", err)
+	}
+	defer file.Close()
+
+	return s.ParseReaderContext(ctx, file)
+}
+
+// readPprofData reads profile, which is gzipped when written by Go runtime
+func readPprofData(reader io.Reader) ([]byte, error) {
+	r := bufio.NewReader(reader)
+	if magic, _ := r.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read gzip: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	} else {
+		reader = r
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read profile: %w", err)
+	}
+	return data, nil
+}
+
+// sampleTypeIndex is index of values of sample type, such as cpu, or default sample type
+func (s *PprofParser) sampleTypeIndex(p *pprofProfile) (int, error) {
+	if len(p.sampleTypes) == 0 {
+		return 0, errors.New("no sample types in profile")
+	}
+
+	name := s.SampleType
+	if name == "" {
+		name = p.string(p.defaultSampleType)
+	}
+	// last sample type is default, as in pprof
+	if name == "" {
+		return len(p.sampleTypes) - 1, nil
+	}
+
+	names := make([]string, len(p.sampleTypes))
+	for i, t := range p.sampleTypes {
+		names[i] = p.string(t.typ)
+		if names[i] == name {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("sample type(%s) not found, expected one of %s", name, strings.Join(names, ", "))
+}
+
+// frames are names of functions in stack of sample from root to leaf, inlined functions are frames too.
+// Locations and functions without names are named by address.
+func (p *pprofProfile) frames(sample pprofSample) []string {
+	var frames []string
+	for i := len(sample.locations) - 1; i >= 0; i-- {
+		loc := p.locations[sample.locations[i]]
+		if len(loc.functions) == 0 {
+			frames = append(frames, fmt.Sprintf("0x%x", loc.address))
+			continue
+		}
+		for j := len(loc.functions) - 1; j >= 0; j-- {
+			name := p.string(p.functions[loc.functions[j]])
+			if name == "" {
+				name = fmt.Sprintf("0x%x", loc.address)
+			}
+			frames = append(frames, name)
+		}
+	}
+	return frames
+}
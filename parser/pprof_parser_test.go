@@ -0,0 +1,92 @@
+package parser
+
+import (
+	"compress/gzip"
+	"os"
+	"testing"
+
+	"github.com/MazenAlkhatib/treemap"
+)
+
+// testdata/cpu.pb.gz is CPU profile of program where main.spin calls main.hashA and main.hashB in loop,
+// with 790ms in 79 samples, 580ms in main.hashA and 210ms in main.hashB, 10ms of it in main.hashB itself.
+
+func TestPprofParserStacks(t *testing.T) {
+	tree, err := (&PprofParser{}).ParseFile("testdata/cpu.pb.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	treemap.SumSizeImputer{}.ImputeSize(*tree)
+
+	spin := "runtime.main/main.main/main.spin"
+	tests := []struct {
+		path string
+		exp  float64
+	}{
+		{path: "runtime.main", exp: 790e6},
+		{path: spin + "/main.hashA", exp: 580e6},
+		{path: spin + "/main.hashB", exp: 210e6},
+		{path: spin + "/main.hashB/(self)", exp: 10e6},
+	}
+	for _, tc := range tests {
+		if got := tree.Nodes[tc.path].Size; got != tc.exp {
+			t.Errorf("%s: exp(%v) != got(%v)", tc.path, tc.exp, got)
+		}
+	}
+
+	if exp, got := "main.hashA", tree.Nodes[spin+"/main.hashA"].Name; exp != got {
+		t.Errorf("name: exp(%s) != got(%s)", exp, got)
+	}
+}
+
+func TestPprofParserByFunction(t *testing.T) {
+	tree, err := (&PprofParser{SampleType: "samples", ByFunction: true}).ParseFile("testdata/cpu.pb.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	treemap.SumSizeImputer{}.ImputeSize(*tree)
+
+	if exp, got := 1.0, tree.Nodes["main/hashB"].Size; exp != got {
+		t.Errorf("main/hashB: exp(%v) != got(%v)", exp, got)
+	}
+	if exp, got := 76.0, tree.Nodes["crypto/internal/fips140/sha256/blockSHANI"].Size; exp != got {
+		t.Errorf("blockSHANI: exp(%v) != got(%v)", exp, got)
+	}
+
+	var total float64
+	for _, root := range tree.To[tree.Root] {
+		total += tree.Nodes[root].Size
+	}
+	if exp := 79.0; total != exp {
+		t.Errorf("total: exp(%v) != got(%v)", exp, total)
+	}
+}
+
+func TestPprofParserUncompressed(t *testing.T) {
+	f, err := os.Open("testdata/cpu.pb.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tree, err := (&PprofParser{SampleType: "cpu"}).ParseReader(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tree.Nodes["runtime.main"]; !ok {
+		t.Errorf("exp runtime.main in (%v)", tree.To[tree.Root])
+	}
+}
+
+func TestPprofParserErrors(t *testing.T) {
+	_, err := (&PprofParser{SampleType: "alloc_space"}).ParseFile("testdata/cpu.pb.gz")
+	assertError(t, err, "sample type(alloc_space) not found, expected one of samples, cpu")
+
+	_, err = decodePprofProfile([]byte{0x0a, 0x05, 0x08})
+	assertError(t, err, "truncated protobuf message")
+}
@@ -0,0 +1,279 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+)
+
+// pprofProfile is part of profile.proto of pprof needed for trees, see github.com/google/pprof/proto/profile.proto
+type pprofProfile struct {
+	sampleTypes       []pprofValueType
+	samples           []pprofSample
+	locations         map[uint64]pprofLocation
+	functions         map[uint64]int64 // name in string table by id of function
+	strings           []string
+	defaultSampleType int64
+}
+
+type pprofValueType struct {
+	typ, unit int64
+}
+
+// pprofSample is values of stack, from leaf to root
+type pprofSample struct {
+	locations []uint64
+	values    []int64
+}
+
+// pprofLocation is address in code, with functions inlined at it from callee to caller
+type pprofLocation struct {
+	address   uint64
+	functions []uint64
+}
+
+// protobuf wire types
+const (
+	wireVarint = 0
+	wire64bit  = 1
+	wireBytes  = 2
+	wire32bit  = 5
+)
+
+// protoReader reads fields of protobuf message
+type protoReader struct {
+	data []byte
+}
+
+var errProtoTruncated = errors.New("truncated protobuf message")
+
+func (r *protoReader) done() bool { return len(r.data) == 0 }
+
+// field reads key of next field
+func (r *protoReader) field() (num int, wire int, err error) {
+	key, err := r.varint()
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(key >> 3), int(key & 7), nil
+}
+
+func (r *protoReader) varint() (uint64, error) {
+	var v uint64
+	for i := 0; i < 10; i++ {
+		if i >= len(r.data) {
+			return 0, errProtoTruncated
+		}
+		b := r.data[i]
+		v |= uint64(b&0x7f) << (7 * i)
+		if b < 0x80 {
+			r.data = r.data[i+1:]
+			return v, nil
+		}
+	}
+	return 0, errors.New("varint overflows 64 bits")
+}
+
+func (r *protoReader) bytes() ([]byte, error) {
+	n, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(r.data)) {
+		return nil, errProtoTruncated
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b, nil
+}
+
+func (r *protoReader) skip(wire int) error {
+	var n uint64
+	switch wire {
+	case wireVarint:
+		_, err := r.varint()
+		return err
+	case wire64bit:
+		n = 8
+	case wire32bit:
+		n = 4
+	case wireBytes:
+		_, err := r.bytes()
+		return err
+	default:
+		return fmt.Errorf("unsupported protobuf wire type(%d)", wire)
+	}
+	if n > uint64(len(r.data)) {
+		return errProtoTruncated
+	}
+	r.data = r.data[n:]
+	return nil
+}
+
+// uint64s reads repeated integers, which are packed or one per field
+func (r *protoReader) uint64s(wire int, vs []uint64) ([]uint64, error) {
+	if wire == wireVarint {
+		v, err := r.varint()
+		return append(vs, v), err
+	}
+	if wire != wireBytes {
+		return vs, fmt.Errorf("unexpected protobuf wire type(%d) of repeated integers", wire)
+	}
+
+	b, err := r.bytes()
+	if err != nil {
+		return vs, err
+	}
+	packed := protoReader{data: b}
+	for !packed.done() {
+		v, err := packed.varint()
+		if err != nil {
+			return vs, err
+		}
+		vs = append(vs, v)
+	}
+	return vs, nil
+}
+
+// message reads embedded message and calls read for each of its fields
+func (r *protoReader) message(wire int, read func(m *protoReader, num, wire int) error) error {
+	if wire != wireBytes {
+		return fmt.Errorf("unexpected protobuf wire type(%d) of message", wire)
+	}
+	b, err := r.bytes()
+	if err != nil {
+		return err
+	}
+	return (&protoReader{data: b}).fields(read)
+}
+
+// fields calls read for each field until end of message
+func (r *protoReader) fields(read func(m *protoReader, num, wire int) error) error {
+	for !r.done() {
+		num, wire, err := r.field()
+		if err != nil {
+			return err
+		}
+		if err := read(r, num, wire); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodePprofProfile decodes uncompressed profile.proto message
+func decodePprofProfile(data []byte) (*pprofProfile, error) {
+	p := pprofProfile{
+		locations: make(map[uint64]pprofLocation),
+		functions: make(map[uint64]int64),
+	}
+
+	err := (&protoReader{data: data}).fields(func(r *protoReader, num, wire int) error {
+		switch num {
+		case 1: // sample_type
+			var vt pprofValueType
+			err := r.message(wire, func(m *protoReader, num, wire int) error {
+				switch num {
+				case 1:
+					v, err := m.varint()
+					vt.typ = int64(v)
+					return err
+				case 2:
+					v, err := m.varint()
+					vt.unit = int64(v)
+					return err
+				}
+				return m.skip(wire)
+			})
+			p.sampleTypes = append(p.sampleTypes, vt)
+			return err
+		case 2: // sample
+			var s pprofSample
+			err := r.message(wire, func(m *protoReader, num, wire int) error {
+				switch num {
+				case 1:
+					var err error
+					s.locations, err = m.uint64s(wire, s.locations)
+					return err
+				case 2:
+					vs, err := m.uint64s(wire, nil)
+					for _, v := range vs {
+						s.values = append(s.values, int64(v))
+					}
+					return err
+				}
+				return m.skip(wire)
+			})
+			p.samples = append(p.samples, s)
+			return err
+		case 4: // location
+			var id uint64
+			var loc pprofLocation
+			err := r.message(wire, func(m *protoReader, num, wire int) error {
+				var err error
+				switch num {
+				case 1:
+					id, err = m.varint()
+					return err
+				case 3:
+					loc.address, err = m.varint()
+					return err
+				case 4: // line
+					return m.message(wire, func(l *protoReader, num, wire int) error {
+						if num == 1 {
+							fn, err := l.varint()
+							loc.functions = append(loc.functions, fn)
+							return err
+						}
+						return l.skip(wire)
+					})
+				}
+				return m.skip(wire)
+			})
+			p.locations[id] = loc
+			return err
+		case 5: // function
+			var id uint64
+			var name int64
+			err := r.message(wire, func(m *protoReader, num, wire int) error {
+				switch num {
+				case 1:
+					var err error
+					id, err = m.varint()
+					return err
+				case 2:
+					v, err := m.varint()
+					name = int64(v)
+					return err
+				}
+				return m.skip(wire)
+			})
+			p.functions[id] = name
+			return err
+		case 6: // string_table
+			if wire != wireBytes {
+				return fmt.Errorf("unexpected protobuf wire type(%d) of string", wire)
+			}
+			b, err := r.bytes()
+			p.strings = append(p.strings, string(b))
+			return err
+		case 14: // default_sample_type
+			v, err := r.varint()
+			p.defaultSampleType = int64(v)
+			return err
+		}
+		return r.skip(wire)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode profile: %w", err)
+	}
+
+	return &p, nil
+}
+
+// string is string in string table, empty when index is out of table
+func (p *pprofProfile) string(i int64) string {
+	if i < 0 || i >= int64(len(p.strings)) {
+		return ""
+	}
+	return p.strings[i]
+}
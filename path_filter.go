@@ -0,0 +1,123 @@
+package treemap
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// PathPattern matches paths of nodes by glob or regular expression.
+// Glob without slash matches last element of path, such as "vendor" or "*_test.go",
+// and glob with slash matches whole path, such as "src/*/testdata".
+// Regular expression is prefixed by "regexp:" and matches anywhere in path, such as "regexp:_test\.go$".
+type PathPattern struct {
+	glob string
+	re   *regexp.Regexp
+}
+
+// ParsePathPattern parses glob or regular expression prefixed by "regexp:".
+func ParsePathPattern(s string) (PathPattern, error) {
+	if expr, ok := strings.CutPrefix(s, "regexp:"); ok {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return PathPattern{}, fmt.Errorf("pattern(%s) is not regular expression: %w", s, err)
+		}
+		return PathPattern{re: re}, nil
+	}
+
+	if _, err := path.Match(s, ""); err != nil {
+		return PathPattern{}, fmt.Errorf("pattern(%s) is not glob: %w", s, err)
+	}
+	return PathPattern{glob: s}, nil
+}
+
+// Match path with "/" separator.
+func (p PathPattern) Match(nodePath string) bool {
+	return p.MatchFormat(PathFormat{}, nodePath)
+}
+
+// MatchFormat matches path with separator of format, glob with separator matches whole path.
+func (p PathPattern) MatchFormat(f PathFormat, nodePath string) bool {
+	if p.re != nil {
+		return p.re.MatchString(nodePath)
+	}
+
+	if !strings.Contains(p.glob, f.separator()) {
+		nodePath = f.Base(nodePath)
+	}
+	ok, _ := path.Match(p.glob, nodePath)
+	return ok
+}
+
+// PathFilter removes nodes with their descendants from tree before layout.
+// Nodes matching any of Exclude patterns are removed.
+// When there are Include patterns, then only nodes matching any of them are kept, with their ancestors and descendants.
+// Ancestors of removed nodes get zero size, so that SumSizeImputer sums sizes of remaining descendants,
+// and parents that lost all children are removed.
+// Expects tree with paths as node identifiers, such as before collapsing long paths.
+type PathFilter struct {
+	Include []PathPattern
+	Exclude []PathPattern
+}
+
+// Filter removes nodes from tree, error is returned when no nodes are left.
+func (f PathFilter) Filter(t *Tree) error {
+	if t == nil {
+		return nil
+	}
+
+	if ok, _ := f.filterNode(t, t.Root, len(f.Include) == 0); !ok {
+		return errors.New("no nodes left after filtering")
+	}
+	return nil
+}
+
+// filterNode returns false when node is removed, and whether its subtree changed
+func (f PathFilter) filterNode(t *Tree, node string, isIncluded bool) (ok, changed bool) {
+	isFakeRoot := node == "some-secret-string"
+
+	if !isFakeRoot && matchAny(f.Exclude, t.Format, node) {
+		deleteSubtree(t, node)
+		return false, true
+	}
+	isIncluded = isIncluded || (!isFakeRoot && matchAny(f.Include, t.Format, node))
+
+	children := t.To[node]
+	kept := make([]string, 0, len(children))
+	for _, child := range children {
+		ok, childChanged := f.filterNode(t, child, isIncluded)
+		if ok {
+			kept = append(kept, child)
+		}
+		changed = changed || childChanged
+	}
+
+	// not included node is kept only as ancestor of included nodes,
+	// and parent that lost all children would be empty box
+	if (!isIncluded || len(children) > 0) && len(kept) == 0 {
+		deleteSubtree(t, node)
+		return false, true
+	}
+
+	if len(kept) < len(children) {
+		t.To[node] = kept
+	}
+	// explicit sizes of ancestors of removed nodes include sizes of removed nodes
+	if n, ok := t.Nodes[node]; ok && changed {
+		n.Size = 0
+		t.Nodes[node] = n
+	}
+
+	return true, changed
+}
+
+func matchAny(patterns []PathPattern, format PathFormat, nodePath string) bool {
+	for _, p := range patterns {
+		if p.MatchFormat(format, nodePath) {
+			return true
+		}
+	}
+	return false
+}
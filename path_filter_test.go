@@ -0,0 +1,187 @@
+package treemap
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestPathPatternMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		exp     bool
+	}{
+		{pattern: "vendor", path: "a/vendor", exp: true},
+		{pattern: "vendor", path: "a/vendor/b", exp: false},
+		{pattern: "*_test.go", path: "a/b/c_test.go", exp: true},
+		{pattern: "*_test.go", path: "a/b/c.go", exp: false},
+		{pattern: "a/*/testdata", path: "a/b/testdata", exp: true},
+		{pattern: "a/*/testdata", path: "x/a/b/testdata", exp: false},
+		{pattern: `regexp:_test\.go$`, path: "a/b/c_test.go", exp: true},
+		{pattern: `regexp:^a/b`, path: "x/a/b", exp: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.pattern+" "+tc.path, func(t *testing.T) {
+			p, err := ParsePathPattern(tc.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := p.Match(tc.path); got != tc.exp {
+				t.Errorf("exp(%v) != got(%v)", tc.exp, got)
+			}
+		})
+	}
+}
+
+func TestParsePathPatternInvalid(t *testing.T) {
+	for _, s := range []string{"[", "regexp:("} {
+		if _, err := ParsePathPattern(s); err == nil {
+			t.Errorf("%s: expected error, got nil", s)
+		}
+	}
+}
+
+func TestPathFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		include  []string
+		exclude  []string
+		expNodes []string
+		expSize  float64
+	}{
+		{
+			name:     "exclude",
+			exclude:  []string{"vendor", "*_test.go"},
+			expNodes: []string{"a", "a/b", "a/b/c.go", "a/testdata", "a/testdata/x"},
+			expSize:  7,
+		},
+		{
+			name:     "include",
+			include:  []string{"b"},
+			expNodes: []string{"a", "a/b", "a/b/c.go", "a/b/c_test.go"},
+			expSize:  3,
+		},
+		{
+			name:     "include and exclude",
+			include:  []string{"regexp:\\.go$"},
+			exclude:  []string{"regexp:_test"},
+			expNodes: []string{"a", "a/b", "a/b/c.go", "a/vendor", "a/vendor/d.go"},
+			expSize:  9,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := Tree{
+				Nodes: map[string]Node{
+					"a":             {Path: "a", Size: 100},
+					"a/b":           {Path: "a/b"},
+					"a/b/c.go":      {Path: "a/b/c.go", Size: 1},
+					"a/b/c_test.go": {Path: "a/b/c_test.go", Size: 2},
+					"a/vendor":      {Path: "a/vendor"},
+					"a/vendor/d.go": {Path: "a/vendor/d.go", Size: 8},
+					"a/testdata":    {Path: "a/testdata"},
+					"a/testdata/x":  {Path: "a/testdata/x", Size: 6},
+				},
+				To: map[string][]string{
+					"a":          {"a/b", "a/vendor", "a/testdata"},
+					"a/b":        {"a/b/c.go", "a/b/c_test.go"},
+					"a/vendor":   {"a/vendor/d.go"},
+					"a/testdata": {"a/testdata/x"},
+				},
+				Root: "a",
+			}
+
+			var f PathFilter
+			for _, s := range tc.include {
+				p, _ := ParsePathPattern(s)
+				f.Include = append(f.Include, p)
+			}
+			for _, s := range tc.exclude {
+				p, _ := ParsePathPattern(s)
+				f.Exclude = append(f.Exclude, p)
+			}
+
+			if err := f.Filter(&tree); err != nil {
+				t.Fatal(err)
+			}
+			SumSizeImputer{EmptyLeafSize: 1}.ImputeSize(tree)
+
+			var got []string
+			for k := range tree.Nodes {
+				got = append(got, k)
+			}
+			sort.Strings(got)
+			if len(got) != len(tc.expNodes) {
+				t.Fatalf("exp(%v) != got(%v)", tc.expNodes, got)
+			}
+			for i := range got {
+				if got[i] != tc.expNodes[i] {
+					t.Errorf("exp(%v) != got(%v)", tc.expNodes, got)
+					break
+				}
+			}
+
+			if size := tree.Nodes["a"].Size; size != tc.expSize {
+				t.Errorf("root size: exp(%v) != got(%v)", tc.expSize, size)
+			}
+		})
+	}
+}
+
+func TestPathFilterZeroesSizesOfAncestors(t *testing.T) {
+	tree := Tree{
+		Nodes: map[string]Node{
+			"r":               {Path: "r", Size: 100},
+			"r/a":             {Path: "r/a", Size: 60},
+			"r/a/b":           {Path: "r/a/b", Size: 50},
+			"r/a/b/x_test.go": {Path: "r/a/b/x_test.go", Size: 40},
+			"r/a/b/c.go":      {Path: "r/a/b/c.go", Size: 10},
+			"r/a/e":           {Path: "r/a/e", Size: 10},
+			"r/d":             {Path: "r/d", Size: 40},
+			"r/d/f":           {Path: "r/d/f", Size: 40},
+			"r/d/f/y_test.go": {Path: "r/d/f/y_test.go", Size: 40},
+		},
+		To: map[string][]string{
+			"r":     {"r/a", "r/d"},
+			"r/a":   {"r/a/b", "r/a/e"},
+			"r/a/b": {"r/a/b/x_test.go", "r/a/b/c.go"},
+			"r/d":   {"r/d/f"},
+			"r/d/f": {"r/d/f/y_test.go"},
+		},
+		Root: "r",
+	}
+
+	p, _ := ParsePathPattern("*_test.go")
+	if err := (PathFilter{Exclude: []PathPattern{p}}).Filter(&tree); err != nil {
+		t.Fatal(err)
+	}
+	SumSizeImputer{EmptyLeafSize: 1}.ImputeSize(tree)
+
+	for path, exp := range map[string]float64{"r": 20, "r/a": 20, "r/a/b": 10, "r/a/e": 10} {
+		if got := tree.Nodes[path].Size; got != exp {
+			t.Errorf("%s: exp(%v) != got(%v)", path, exp, got)
+		}
+	}
+	for _, path := range []string{"r/d", "r/d/f"} {
+		if _, ok := tree.Nodes[path]; ok {
+			t.Errorf("%s: parent without children is not removed", path)
+		}
+	}
+	if exp, got := []string{"r/a"}, tree.To["r"]; len(got) != 1 || got[0] != exp[0] {
+		t.Errorf("exp(%v) != got(%v)", exp, got)
+	}
+}
+
+func TestPathFilterNoNodesLeft(t *testing.T) {
+	tree := Tree{
+		Nodes: map[string]Node{"a": {Path: "a", Size: 1}},
+		Root:  "a",
+	}
+
+	p, _ := ParsePathPattern("a")
+	if err := (PathFilter{Exclude: []PathPattern{p}}).Filter(&tree); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
@@ -0,0 +1,150 @@
+package treemap
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultSeparator separates parts of paths when PathFormat has no separator.
+const DefaultSeparator = "/"
+
+// PathFormat is how paths of nodes are split into parts, such as "/" for files, "." for Java packages or "::" for Rust modules.
+// Parts can contain separator when it is preceded by Escape, such as `a\.b` is single part with "." separator and '\' escape.
+// Paths and names of nodes are kept escaped, and are unescaped only for display.
+// Zero value is "/" separator without escaping.
+type PathFormat struct {
+	Separator string
+	Escape    rune // escapes separator and itself, no escaping when zero
+}
+
+func (f PathFormat) separator() string {
+	if f.Separator == "" {
+		return DefaultSeparator
+	}
+	return f.Separator
+}
+
+// Split path into escaped parts.
+func (f PathFormat) Split(path string) []string {
+	if f.Escape == 0 {
+		return strings.Split(path, f.separator())
+	}
+
+	sep := f.separator()
+	var parts []string
+	start := 0
+	for i := 0; i < len(path); {
+		r, n := utf8.DecodeRuneInString(path[i:])
+		switch {
+		case r == f.Escape:
+			// skip escaped rune
+			i += n
+			if i < len(path) {
+				_, n = utf8.DecodeRuneInString(path[i:])
+				i += n
+			}
+		case strings.HasPrefix(path[i:], sep):
+			parts = append(parts, path[start:i])
+			i += len(sep)
+			start = i
+		default:
+			i += n
+		}
+	}
+	return append(parts, path[start:])
+}
+
+// Join escaped parts into path.
+func (f PathFormat) Join(parts ...string) string {
+	return strings.Join(parts, f.separator())
+}
+
+// Base is last escaped part of path.
+func (f PathFormat) Base(path string) string {
+	if i := f.lastSeparator(path); i >= 0 {
+		return path[i+len(f.separator()):]
+	}
+	return path
+}
+
+// Parent is path without last part, false when path has single part.
+func (f PathFormat) Parent(path string) (string, bool) {
+	if i := f.lastSeparator(path); i >= 0 {
+		return path[:i], true
+	}
+	return "", false
+}
+
+// HasTrailingSeparator is true when path ends with separator that is not escaped.
+func (f PathFormat) HasTrailingSeparator(path string) bool {
+	return path != "" && f.lastSeparator(path) == len(path)-len(f.separator())
+}
+
+// IsPrefix is true when path is equal to prefix or is inside of it.
+func (f PathFormat) IsPrefix(prefix, path string) bool {
+	if path == prefix {
+		return true
+	}
+	end := len(prefix) + len(f.separator())
+	if len(path) < end || !strings.HasPrefix(path, prefix) {
+		return false
+	}
+	return f.lastSeparator(path[:end]) == len(prefix)
+}
+
+// Unescape removes escapes from part or whole path, for display.
+func (f PathFormat) Unescape(s string) string {
+	if f.Escape == 0 || !strings.ContainsRune(s, f.Escape) {
+		return s
+	}
+
+	var b strings.Builder
+	isEscaped := false
+	for _, r := range s {
+		if r == f.Escape && !isEscaped {
+			isEscaped = true
+			continue
+		}
+		isEscaped = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// EscapePart escapes separator and escape in part of path.
+// Part is returned as is when there is no escape.
+func (f PathFormat) EscapePart(part string) string {
+	if f.Escape == 0 {
+		return part
+	}
+	esc := string(f.Escape)
+	part = strings.ReplaceAll(part, esc, esc+esc)
+	return strings.ReplaceAll(part, f.separator(), esc+f.separator())
+}
+
+// lastSeparator is index of last separator that is not escaped, -1 when there is none
+func (f PathFormat) lastSeparator(path string) int {
+	sep := f.separator()
+	if f.Escape == 0 {
+		return strings.LastIndex(path, sep)
+	}
+
+	last := -1
+	for i := 0; i < len(path); {
+		r, n := utf8.DecodeRuneInString(path[i:])
+		switch {
+		case r == f.Escape:
+			i += n
+			if i < len(path) {
+				_, n = utf8.DecodeRuneInString(path[i:])
+				i += n
+			}
+		case strings.HasPrefix(path[i:], sep):
+			last = i
+			i += len(sep)
+		default:
+			i += n
+		}
+	}
+	return last
+}
@@ -0,0 +1,69 @@
+package treemap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPathFormatSplit(t *testing.T) {
+	tests := []struct {
+		format PathFormat
+		path   string
+		exp    []string
+	}{
+		{format: PathFormat{}, path: "a/b/c", exp: []string{"a", "b", "c"}},
+		{format: PathFormat{Separator: "."}, path: "com.example.Foo", exp: []string{"com", "example", "Foo"}},
+		{format: PathFormat{Separator: "::"}, path: "std::io::Read", exp: []string{"std", "io", "Read"}},
+		{format: PathFormat{Separator: `\`}, path: `C:\Users\a`, exp: []string{"C:", "Users", "a"}},
+		{format: PathFormat{Escape: '\\'}, path: `a/b\/c/d`, exp: []string{"a", `b\/c`, "d"}},
+		{format: PathFormat{Escape: '\\'}, path: `a\\/b`, exp: []string{`a\\`, "b"}},
+		{format: PathFormat{Separator: "::", Escape: '^'}, path: "a::b^::c", exp: []string{"a", "b^::c"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			got := tc.format.Split(tc.path)
+			if !reflect.DeepEqual(tc.exp, got) {
+				t.Errorf("exp(%#v) != got(%#v)", tc.exp, got)
+			}
+			if path := tc.format.Join(got...); path != tc.path {
+				t.Errorf("join: exp(%s) != got(%s)", tc.path, path)
+			}
+		})
+	}
+}
+
+func TestPathFormatBaseAndParent(t *testing.T) {
+	f := PathFormat{Separator: ".", Escape: '\\'}
+
+	if exp, got := `c\.d`, f.Base(`a.b.c\.d`); exp != got {
+		t.Errorf("base: exp(%s) != got(%s)", exp, got)
+	}
+	if parent, ok := f.Parent(`a.b.c\.d`); !ok || parent != "a.b" {
+		t.Errorf("parent: exp(a.b) != got(%s, %v)", parent, ok)
+	}
+	if _, ok := f.Parent(`a\.b`); ok {
+		t.Error("exp no parent of single part")
+	}
+	if !f.HasTrailingSeparator("a.b.") || f.HasTrailingSeparator(`a.b\.`) {
+		t.Error("wrong trailing separator")
+	}
+	if !f.IsPrefix("a.b", "a.b.c") || f.IsPrefix("a.b", "a.bc") || f.IsPrefix(`a.b\`, `a.b\.c`) {
+		t.Error("wrong prefix")
+	}
+}
+
+func TestPathFormatEscape(t *testing.T) {
+	f := PathFormat{Separator: ".", Escape: '\\'}
+
+	escaped := f.EscapePart(`a.b\c`)
+	if exp := `a\.b\\c`; escaped != exp {
+		t.Errorf("escape: exp(%s) != got(%s)", exp, escaped)
+	}
+	if exp, got := `a.b\c`, f.Unescape(escaped); exp != got {
+		t.Errorf("unescape: exp(%s) != got(%s)", exp, got)
+	}
+	if exp, got := `x\y`, (PathFormat{}).Unescape(`x\y`); exp != got {
+		t.Errorf("no escape: exp(%s) != got(%s)", exp, got)
+	}
+}
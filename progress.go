@@ -0,0 +1,86 @@
+package treemap
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/schollz/progressbar/v3"
+)
+
+// Progress observes long running passes, such as parsing, passes over tree and rendering.
+// Each pass calls Start, then Add as it processes items, and Finish when it is done.
+// Passes do not run concurrently.
+type Progress interface {
+	Start(description string, total int64) // total is -1 when unknown
+	Add(n int)
+	Finish()
+}
+
+// ProgressOrNop returns NopProgress when p is nil.
+func ProgressOrNop(p Progress) Progress {
+	if p == nil {
+		return NopProgress{}
+	}
+	return p
+}
+
+// NopProgress does not report anything. It is default for all passes.
+type NopProgress struct{}
+
+func (NopProgress) Start(description string, total int64) {}
+
+func (NopProgress) Add(n int) {}
+
+func (NopProgress) Finish() {}
+
+// BarProgress draws progress bar in terminal for each pass.
+type BarProgress struct {
+	bar *progressbar.ProgressBar
+}
+
+func (p *BarProgress) Start(description string, total int64) {
+	p.bar = progressbar.Default(total, description)
+}
+
+func (p *BarProgress) Add(n int) {
+	if p.bar != nil {
+		p.bar.Add(n)
+	}
+}
+
+func (p *BarProgress) Finish() {
+	if p.bar != nil {
+		p.bar.Finish()
+		p.bar = nil
+	}
+}
+
+// SlogProgress logs start and finish of each pass with number of processed items and duration.
+type SlogProgress struct {
+	Logger *slog.Logger // default logger when not set
+
+	description string
+	total       int64
+	count       int64
+	start       time.Time
+}
+
+func (p *SlogProgress) Start(description string, total int64) {
+	p.description, p.total, p.count, p.start = description, total, 0, time.Now()
+	p.logger().Info("started", "pass", description, "total", total)
+}
+
+func (p *SlogProgress) Add(n int) {
+	p.count += int64(n)
+}
+
+func (p *SlogProgress) Finish() {
+	p.logger().Info("finished", "pass", p.description, "count", p.count, "duration", time.Since(p.start))
+}
+
+func (p *SlogProgress) logger() *slog.Logger {
+	if p.Logger == nil {
+		return slog.Default()
+	}
+	return p.Logger
+}
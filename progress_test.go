@@ -0,0 +1,88 @@
+package treemap
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type countingProgress struct {
+	passes []string
+	count  int
+}
+
+func (p *countingProgress) Start(description string, total int64) {
+	p.passes = append(p.passes, description)
+}
+
+func (p *countingProgress) Add(n int) { p.count += n }
+
+func (p *countingProgress) Finish() {}
+
+func TestProgressReportedByPasses(t *testing.T) {
+	tree := Tree{
+		Nodes: map[string]Node{
+			"a":     {Path: "a"},
+			"a/b":   {Path: "a/b"},
+			"a/b/c": {Path: "a/b/c", Size: 1},
+		},
+		To: map[string][]string{
+			"a":   {"a/b"},
+			"a/b": {"a/b/c"},
+		},
+		Root: "a",
+	}
+
+	progress := &countingProgress{}
+	SetNamesFromPathsContext(context.Background(), &tree, progress)
+	CollapseLongPathsContext(context.Background(), &tree, progress)
+	SumSizeImputer{EmptyLeafSize: 1, Progress: progress}.ImputeSize(tree)
+
+	if exp, got := 3, len(progress.passes); exp != got {
+		t.Errorf("exp(%d) != got(%d): %v", exp, got, progress.passes)
+	}
+	if progress.count == 0 {
+		t.Errorf("exp items to be reported")
+	}
+}
+
+func TestPassesFromNodeWithoutBar(t *testing.T) {
+	tree := Tree{
+		Nodes: map[string]Node{
+			"a":     {Path: "a", Name: "a"},
+			"a/b":   {Path: "a/b", Name: "b"},
+			"a/b/c": {Path: "a/b/c", Name: "c", Size: 1},
+		},
+		To: map[string][]string{
+			"a":   {"a/b"},
+			"a/b": {"a/b/c"},
+		},
+		Root: "a",
+	}
+
+	CollapseLongPathsFromNode(&tree, tree.Root, nil)
+	SumSizeImputer{EmptyLeafSize: 1}.ImputeSizeNode(tree, tree.Root, nil)
+
+	if n := tree.Nodes["a"]; n.Name != "a/b/c" || n.Size != 1 {
+		t.Errorf("wrong collapsed node: %#v", n)
+	}
+}
+
+func TestSlogProgress(t *testing.T) {
+	var out bytes.Buffer
+	p := &SlogProgress{Logger: slog.New(slog.NewTextHandler(&out, nil))}
+
+	p.Start("pass", 3)
+	p.Add(2)
+	p.Add(1)
+	p.Finish()
+
+	got := out.String()
+	for _, exp := range []string{"msg=started pass=pass total=3", "msg=finished pass=pass count=3"} {
+		if !strings.Contains(got, exp) {
+			t.Errorf("exp(%s) not in got(%s)", exp, got)
+		}
+	}
+}
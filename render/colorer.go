@@ -20,3 +20,11 @@ func (s NoneColorer) ColorBox(tree treemap.Tree, node string) color.Color {
 func (s NoneColorer) ColorText(tree treemap.Tree, node string) color.Color {
 	return DarkTextColor
 }
+
+func (s NoneColorer) ColorBoxCompact(tree *treemap.CompactTree, node int32) color.Color {
+	return color.Transparent
+}
+
+func (s NoneColorer) ColorTextCompact(tree *treemap.CompactTree, node int32) color.Color {
+	return DarkTextColor
+}
@@ -42,11 +42,21 @@ var paletteReBuCSV string
 //go:embed palettes/RdYlGn.csv
 var paletteRdYlGnCSV string
 
+// Reversed returns palette with colors in reverse order, such as blue to red for RdBu.
+func (gt ColorfulPalette) Reversed() ColorfulPalette {
+	r := make(ColorfulPalette, len(gt))
+	for i, c := range gt {
+		r[len(gt)-1-i].Col = c.Col
+		r[len(gt)-1-i].Pos = 1 - c.Pos
+	}
+	return r
+}
+
 func makePaletteFromCSV(csv string) ColorfulPalette {
 	rows := strings.Split(csv, "\n")
-	palette := make(ColorfulPalette, len(rows))
+	palette := make(ColorfulPalette, 0, len(rows))
 
-	for i, row := range rows {
+	for _, row := range rows {
 		parts := strings.Split(row, ",")
 		if len(parts) != 2 {
 			continue
@@ -62,8 +72,7 @@ func makePaletteFromCSV(csv string) ColorfulPalette {
 			log.Fatal(err)
 		}
 
-		palette[i].Col = c
-		palette[i].Pos = v
+		palette = append(palette, ColorfulPalette{{Col: c, Pos: v}}...)
 	}
 
 	return palette
@@ -14,3 +14,30 @@ func TestGetPaletteBasic(t *testing.T) {
 		t.Errorf("exp(%#v) != got(%#v)", expColor, palette[0].Col)
 	}
 }
+
+func TestGetPaletteSkipsEmptyRows(t *testing.T) {
+	for _, name := range []string{"RdBu", "RdYlGn"} {
+		palette, ok := GetPalette(name)
+		if !ok {
+			t.Fatalf("%s: palette not found", name)
+		}
+		if last := palette[len(palette)-1]; last.Pos != 1 {
+			t.Errorf("%s: last keypoint exp(1) != got(%v)", name, last.Pos)
+		}
+	}
+}
+
+func TestColorfulPaletteReversed(t *testing.T) {
+	palette, _ := GetPalette("RdBu")
+	reversed := palette.Reversed()
+
+	if len(reversed) != len(palette) {
+		t.Fatalf("exp(%d) != got(%d)", len(palette), len(reversed))
+	}
+	for _, v := range []float64{0, 0.25, 0.5, 0.8, 1} {
+		exp, got := palette.GetInterpolatedColorFor(1-v).(colorful.Color), reversed.GetInterpolatedColorFor(v).(colorful.Color)
+		if !exp.AlmostEqualRgb(got) {
+			t.Errorf("%v: exp(%#v) != got(%#v)", v, exp, got)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package render
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/MazenAlkhatib/treemap"
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// NoHeatColor is used for nodes that do not have heat.
+var NoHeatColor color.Color = color.RGBA{R: 220, G: 220, B: 220, A: 255}
+
+// HeatColorer colors boxes by heat of nodes mapped through palette.
+// Heat is normalized into [0, 1] by domain [MinHeat, MaxHeat], values outside of domain are clamped.
+type HeatColorer struct {
+	Palette ColorfulPalette
+	MinHeat float64
+	MaxHeat float64
+}
+
+// NewHeatColorer makes colorer with domain of heat from min and max heat in tree.
+func NewHeatColorer(tree treemap.Tree, palette ColorfulPalette) HeatColorer {
+	minHeat, maxHeat, _ := HeatDomain(tree)
+	return HeatColorer{
+		Palette: palette,
+		MinHeat: minHeat,
+		MaxHeat: maxHeat,
+	}
+}
+
+func (s HeatColorer) ColorBox(tree treemap.Tree, node string) color.Color {
+	n, ok := tree.Nodes[node]
+	if !ok || !n.HasHeat || len(s.Palette) == 0 {
+		return NoHeatColor
+	}
+	return s.Palette.GetInterpolatedColorFor(s.normalize(n.Heat))
+}
+
+func (s HeatColorer) ColorText(tree treemap.Tree, node string) color.Color {
+	return textColorForBox(s.ColorBox(tree, node))
+}
+
+func (s HeatColorer) ColorBoxCompact(tree *treemap.CompactTree, node int32) color.Color {
+	n := tree.Nodes[node]
+	if !n.HasHeat || len(s.Palette) == 0 {
+		return NoHeatColor
+	}
+	return s.Palette.GetInterpolatedColorFor(s.normalize(n.Heat))
+}
+
+func (s HeatColorer) ColorTextCompact(tree *treemap.CompactTree, node int32) color.Color {
+	return textColorForBox(s.ColorBoxCompact(tree, node))
+}
+
+// textColorForBox is dark on light boxes and light on dark boxes
+func textColorForBox(c color.Color) color.Color {
+	boxColor, ok := colorful.MakeColor(c)
+	if !ok {
+		return DarkTextColor
+	}
+	_, _, l := boxColor.Hcl()
+	switch {
+	case l > 0.5:
+		return DarkTextColor
+	default:
+		return LightTextColor
+	}
+}
+
+// normalize heat into [0, 1]. When domain is empty, then heat is in middle.
+func (s HeatColorer) normalize(heat float64) float64 {
+	if s.MaxHeat <= s.MinHeat {
+		return 0.5
+	}
+	v := (heat - s.MinHeat) / (s.MaxHeat - s.MinHeat)
+	return math.Max(0, math.Min(1, v))
+}
+
+// CompactHeatDomain returns min and max heat of nodes in compact tree.
+// Returns false when no node has heat.
+func CompactHeatDomain(tree *treemap.CompactTree) (minHeat, maxHeat float64, ok bool) {
+	for _, n := range tree.Nodes {
+		if !n.HasHeat {
+			continue
+		}
+		if !ok || n.Heat < minHeat {
+			minHeat = n.Heat
+		}
+		if !ok || n.Heat > maxHeat {
+			maxHeat = n.Heat
+		}
+		ok = true
+	}
+	return minHeat, maxHeat, ok
+}
+
+// HeatDomain returns min and max heat of nodes in tree.
+// Returns false when no node has heat.
+func HeatDomain(tree treemap.Tree) (minHeat, maxHeat float64, ok bool) {
+	for _, n := range tree.Nodes {
+		if !n.HasHeat {
+			continue
+		}
+		if !ok || n.Heat < minHeat {
+			minHeat = n.Heat
+		}
+		if !ok || n.Heat > maxHeat {
+			maxHeat = n.Heat
+		}
+		ok = true
+	}
+	return minHeat, maxHeat, ok
+}
@@ -0,0 +1,82 @@
+package render
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/MazenAlkhatib/treemap"
+)
+
+func TestHeatDomain(t *testing.T) {
+	tree := treemap.Tree{
+		Nodes: map[string]treemap.Node{
+			"a":   {Path: "a", Heat: 100},
+			"a/b": {Path: "a/b", Heat: -1, HasHeat: true},
+			"a/c": {Path: "a/c", Heat: 5, HasHeat: true},
+		},
+		Root: "a",
+	}
+
+	minHeat, maxHeat, ok := HeatDomain(tree)
+	if !ok || minHeat != -1 || maxHeat != 5 {
+		t.Errorf("exp(-1, 5, true) != got(%v, %v, %v)", minHeat, maxHeat, ok)
+	}
+
+	if _, _, ok := HeatDomain(treemap.Tree{}); ok {
+		t.Error("expected no domain for empty tree")
+	}
+}
+
+func TestHeatColorer(t *testing.T) {
+	palette, _ := GetPalette("RdBu")
+	tree := treemap.Tree{
+		Nodes: map[string]treemap.Node{
+			"a":   {Path: "a"},
+			"a/b": {Path: "a/b", Heat: 0, HasHeat: true},
+			"a/c": {Path: "a/c", Heat: 10, HasHeat: true},
+			"a/d": {Path: "a/d", Heat: 20, HasHeat: true},
+		},
+		Root: "a",
+	}
+	colorer := NewHeatColorer(tree, palette)
+
+	tests := []struct {
+		node     string
+		expColor color.Color
+	}{
+		{node: "a", expColor: NoHeatColor},
+		{node: "a/b", expColor: palette.GetInterpolatedColorFor(0)},
+		{node: "a/c", expColor: palette.GetInterpolatedColorFor(0.5)},
+		{node: "a/d", expColor: palette.GetInterpolatedColorFor(1)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.node, func(t *testing.T) {
+			if c := colorer.ColorBox(tree, tc.node); c != tc.expColor {
+				t.Errorf("exp(%#v) != got(%#v)", tc.expColor, c)
+			}
+		})
+	}
+
+	// dark red at low end needs light text, white-ish middle needs dark text
+	if c := colorer.ColorText(tree, "a/b"); c != LightTextColor {
+		t.Errorf("exp light text, got(%#v)", c)
+	}
+	if c := colorer.ColorText(tree, "a/c"); c != DarkTextColor {
+		t.Errorf("exp dark text, got(%#v)", c)
+	}
+}
+
+func TestHeatColorerExplicitDomainClamps(t *testing.T) {
+	palette, _ := GetPalette("RdYlGn")
+	tree := treemap.Tree{
+		Nodes: map[string]treemap.Node{
+			"a": {Path: "a", Heat: 1000, HasHeat: true},
+		},
+		Root: "a",
+	}
+	colorer := HeatColorer{Palette: palette, MinHeat: 0, MaxHeat: 1}
+
+	if c, exp := colorer.ColorBox(tree, "a"), palette.GetInterpolatedColorFor(1); c != exp {
+		t.Errorf("exp(%#v) != got(%#v)", exp, c)
+	}
+}
@@ -0,0 +1,227 @@
+package render
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"image/color"
+	"io"
+	"os"
+
+	"github.com/MazenAlkhatib/treemap"
+	"github.com/MazenAlkhatib/treemap/layout"
+)
+
+//go:embed templates/treemap.html
+var htmlTemplateText string
+
+var htmlTemplate = template.Must(template.New("treemap").Parse(htmlTemplateText))
+
+// HTMLRenderer renders self-contained interactive HTML page.
+// Page shows computed layout first and lays out subtrees in browser when zooming into them,
+// so it contains all nodes of tree and their colors.
+// Margin, Padding and Layout are used for layouts in browser, and should be same as for computed layout.
+type HTMLRenderer struct {
+	Colorer     Colorer
+	BorderColor color.Color
+	Margin      float64
+	Padding     float64
+	Layout      string           // name of layout as for layout.GetLayout, squarify when not set
+	Progress    treemap.Progress // no progress is reported when not set
+}
+
+type htmlNode struct {
+	Name      string  `json:"name"`
+	Path      string  `json:"path"`
+	Size      float64 `json:"size"`
+	Heat      float64 `json:"heat"`
+	HasHeat   bool    `json:"hasHeat"`
+	Parent    int     `json:"parent"`
+	Children  []int   `json:"children,omitempty"`
+	Color     string  `json:"color"`
+	TextColor string  `json:"textColor"`
+}
+
+type htmlText struct {
+	Text  string  `json:"text"`
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
+	H     float64 `json:"h"`
+	Scale float64 `json:"scale"`
+}
+
+type htmlBox struct {
+	Node  int       `json:"node"`
+	X     float64   `json:"x"`
+	Y     float64   `json:"y"`
+	W     float64   `json:"w"`
+	H     float64   `json:"h"`
+	Title *htmlText `json:"title,omitempty"`
+}
+
+type htmlData struct {
+	W                    float64    `json:"w"`
+	H                    float64    `json:"h"`
+	Margin               float64    `json:"margin"`
+	Padding              float64    `json:"padding"`
+	PaddingRoot          float64    `json:"paddingRoot"`
+	FontSize             int        `json:"fontSize"`
+	TextWidthMultiplier  float64    `json:"textWidthMultiplier"`
+	TextHeightMultiplier float64    `json:"textHeightMultiplier"`
+	TextMarginH          float64    `json:"textMarginH"`
+	TooSmallBoxWidth     float64    `json:"tooSmallBoxWidth"`
+	TooSmallBoxHeight    float64    `json:"tooSmallBoxHeight"`
+	Title                string     `json:"title,omitempty"`
+	BorderColor          string     `json:"borderColor"`
+	LayoutName           string     `json:"layoutName"`
+	Nodes                []htmlNode `json:"nodes"`
+	Layout               []htmlBox  `json:"layout"`
+}
+
+// Render renders the treemap into HTML file
+func (r HTMLRenderer) Render(tree treemap.Tree, root UIBox, w, h float64, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	if err := r.RenderTo(tree, root, w, h, file); err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+// RenderTo renders the treemap as HTML page to writer
+func (r HTMLRenderer) RenderTo(tree treemap.Tree, root UIBox, w, h float64, out io.Writer) error {
+	if !root.IsRoot {
+		return fmt.Errorf("not a root node")
+	}
+	if _, ok := layout.GetLayout(r.layoutName()); !ok {
+		return fmt.Errorf("layout(%s) is not known", r.Layout)
+	}
+
+	bar := treemap.ProgressOrNop(r.Progress)
+	bar.Start("Rendering HTML tree map", -1)
+	defer bar.Finish()
+
+	data := r.makeData(tree, root, w, h)
+	if err := htmlTemplate.Execute(out, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return nil
+}
+
+func (r HTMLRenderer) makeData(tree treemap.Tree, root UIBox, w, h float64) htmlData {
+	colorer := r.Colorer
+	if colorer == nil {
+		colorer = NoneColorer{}
+	}
+
+	data := htmlData{
+		W:                    w,
+		H:                    h,
+		Margin:               r.Margin,
+		Padding:              r.Padding,
+		PaddingRoot:          root.X,
+		FontSize:             fontSize,
+		TextWidthMultiplier:  textWidthMultiplier,
+		TextHeightMultiplier: textHeightMultiplier,
+		TextMarginH:          textMarginH,
+		TooSmallBoxWidth:     tooSmallBoxWidth,
+		TooSmallBoxHeight:    tooSmallBoxHeight,
+		BorderColor:          cssColor(r.BorderColor, color.White),
+		LayoutName:           r.layoutName(),
+	}
+	if root.Title != nil {
+		data.Title = root.Title.Text
+	}
+
+	// nodes in breadth first order, root is first
+	order := []string{tree.Root}
+	parents := []int{-1}
+	ids := map[string]int{tree.Root: 0}
+	for i := 0; i < len(order); i++ {
+		for _, child := range tree.To[order[i]] {
+			if _, ok := ids[child]; ok {
+				continue
+			}
+			ids[child] = len(order)
+			order = append(order, child)
+			parents = append(parents, i)
+		}
+	}
+
+	data.Nodes = make([]htmlNode, len(order))
+	for i, q := range order {
+		n := tree.Nodes[q]
+		name := entityToSlash.Replace(tree.Format.Unescape(n.Name))
+		path := entityToSlash.Replace(tree.Format.Unescape(n.Path))
+		if q == "some-secret-string" {
+			name, path = "", ""
+		}
+
+		data.Nodes[i] = htmlNode{
+			Name:      name,
+			Path:      path,
+			Size:      nodeSize(tree, q),
+			Heat:      n.Heat,
+			HasHeat:   n.HasHeat,
+			Parent:    parents[i],
+			Color:     cssColor(colorer.ColorBox(tree, q), color.White),
+			TextColor: cssColor(colorer.ColorText(tree, q), DarkTextColor),
+		}
+		if p := parents[i]; p >= 0 {
+			data.Nodes[p].Children = append(data.Nodes[p].Children, i)
+		}
+	}
+
+	// computed layout in same order as in SVG
+	que := []UIBox{root}
+	var q UIBox
+	for len(que) > 0 {
+		q, que = que[0], que[1:]
+		que = append(que, q.Children...)
+
+		id, ok := ids[q.Path]
+		if q.IsInvisible || !ok {
+			continue
+		}
+
+		box := htmlBox{Node: id, X: q.X, Y: q.Y, W: q.W, H: q.H}
+		if q.Title != nil {
+			box.Title = &htmlText{
+				Text:  q.Title.Text,
+				X:     q.Title.X,
+				Y:     q.Title.Y,
+				H:     q.Title.H,
+				Scale: q.Title.Scale,
+			}
+		}
+		data.Layout = append(data.Layout, box)
+	}
+
+	return data
+}
+
+func (r HTMLRenderer) layoutName() string {
+	if r.Layout == "" {
+		return "squarify"
+	}
+	return r.Layout
+}
+
+// cssColor formats color for CSS, nil color is replaced by default.
+func cssColor(c color.Color, def color.Color) string {
+	if c == nil {
+		c = def
+	}
+	r, g, b, a := c.RGBA()
+	if a == 0 {
+		return "transparent"
+	}
+	// RGBA is alpha-premultiplied
+	return fmt.Sprintf("rgba(%d,%d,%d,%.2f)", r*0xff/a, g*0xff/a, b*0xff/a, float64(a)/0xffff)
+}
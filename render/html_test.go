@@ -0,0 +1,127 @@
+package render
+
+import (
+	"bytes"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/MazenAlkhatib/treemap"
+	"github.com/MazenAlkhatib/treemap/layout"
+)
+
+func TestHTMLRendererMakeData(t *testing.T) {
+	tree := treemap.Tree{
+		Nodes: map[string]treemap.Node{
+			"a":   {Path: "a", Name: "a", Size: 3},
+			"a/b": {Path: "a/b", Name: "b", Size: 1, Heat: 2, HasHeat: true},
+			"a/c": {Path: "a/c", Name: "c", Size: 2},
+		},
+		To: map[string][]string{
+			"a": {"a/b", "a/c"},
+		},
+		Root: "a",
+	}
+	root := UIBox{
+		IsRoot:      true,
+		IsInvisible: true,
+		Children: []UIBox{
+			{
+				Path: "a", X: 1, Y: 1, W: 10, H: 10,
+				Children: []UIBox{{Path: "a/c", X: 2, Y: 2, W: 3, H: 3}},
+			},
+		},
+	}
+
+	data := HTMLRenderer{Colorer: NoneColorer{}, BorderColor: color.Black}.makeData(tree, root, 12, 12)
+
+	if len(data.Nodes) != 3 {
+		t.Fatalf("exp(3) nodes != got(%d)", len(data.Nodes))
+	}
+	if n := data.Nodes[0]; n.Path != "a" || n.Parent != -1 || len(n.Children) != 2 {
+		t.Errorf("wrong root node: %#v", n)
+	}
+	if n := data.Nodes[1]; n.Path != "a/b" || n.Parent != 0 || !n.HasHeat || n.Heat != 2 {
+		t.Errorf("wrong child node: %#v", n)
+	}
+	if data.BorderColor != "rgba(0,0,0,1.00)" {
+		t.Errorf("wrong border color: %s", data.BorderColor)
+	}
+
+	expLayout := []htmlBox{
+		{Node: 0, X: 1, Y: 1, W: 10, H: 10},
+		{Node: 2, X: 2, Y: 2, W: 3, H: 3},
+	}
+	if len(data.Layout) != len(expLayout) {
+		t.Fatalf("exp(%d) boxes != got(%d)", len(expLayout), len(data.Layout))
+	}
+	for i := range expLayout {
+		if data.Layout[i] != expLayout[i] {
+			t.Errorf("box(%d): exp(%#v) != got(%#v)", i, expLayout[i], data.Layout[i])
+		}
+	}
+}
+
+func TestHTMLRendererRenderToEscapesScript(t *testing.T) {
+	tree := treemap.Tree{
+		Nodes: map[string]treemap.Node{
+			"</script>": {Path: "</script>", Name: "</script>", Size: 1},
+		},
+		Root: "</script>",
+	}
+	root := UIBox{IsRoot: true, IsInvisible: true}
+
+	var buf bytes.Buffer
+	if err := (HTMLRenderer{}).RenderTo(tree, root, 10, 10, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := strings.Count(buf.String(), "</script>"); n != 1 {
+		t.Errorf("exp(1) closing script tag != got(%d)", n)
+	}
+}
+
+func TestHTMLRendererLayout(t *testing.T) {
+	tree := treemap.Tree{
+		Nodes: map[string]treemap.Node{
+			"a":   {Path: "a", Name: "a", Size: 3},
+			"a/b": {Path: "a/b", Name: "b", Size: 1},
+			"a/c": {Path: "a/c", Name: "c", Size: 2},
+		},
+		To:   map[string][]string{"a": {"a/b", "a/c"}},
+		Root: "a",
+	}
+
+	builder := UITreeMapBuilder{Colorer: NoneColorer{}, Layout: layout.LayoutFunc(layout.SliceAndDice)}
+	root := builder.NewUITreeMap(tree, 100, 100, 4, 4, 8)
+
+	// computed layout keeps order of children, which squarified layout would not
+	data := HTMLRenderer{Layout: "slice-dice"}.makeData(tree, root, 100, 100)
+	boxes := make(map[int]htmlBox)
+	for _, b := range data.Layout {
+		boxes[b.Node] = b
+	}
+	if b, c := boxes[1], boxes[2]; b.W == 0 || c.W == 0 || b.X >= c.X || b.Y != c.Y {
+		t.Errorf("exp boxes of b and c side by side in order, got %#v and %#v", b, c)
+	}
+
+	var buf bytes.Buffer
+	if err := (HTMLRenderer{Layout: "slice-dice"}).RenderTo(tree, root, 100, 100, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"layoutName":"slice-dice"`) {
+		t.Errorf("layout for zooming is not in page")
+	}
+
+	if err := (HTMLRenderer{}).RenderTo(tree, root, 100, 100, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"layoutName":"squarify"`) {
+		t.Errorf("default layout for zooming is not squarify")
+	}
+
+	err := (HTMLRenderer{Layout: "spiral"}).RenderTo(tree, root, 100, 100, &buf)
+	if err == nil || !strings.Contains(err.Error(), "layout(spiral) is not known") {
+		t.Errorf("exp error of unknown layout, got %v", err)
+	}
+}
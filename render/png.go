@@ -0,0 +1,210 @@
+package render
+
+import (
+	_ "embed"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/MazenAlkhatib/treemap"
+)
+
+const (
+	glyphCols     int = 5
+	glyphRows     int = 7
+	glyphCellCols int = glyphCols + 1 // with spacing between glyphs
+)
+
+//go:embed fonts/5x7.txt
+var font5x7Text string
+
+// font5x7 is bitmap font used in raster images, each glyph row is bitmask with highest bit on the left.
+var font5x7 = makeBitmapFontFromText(font5x7Text)
+
+func makeBitmapFontFromText(text string) map[rune][glyphRows]uint8 {
+	font := make(map[rune][glyphRows]uint8)
+
+	var rows []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "# ") {
+			continue
+		}
+		rows = append(rows, line)
+	}
+
+	for i := 0; i+glyphRows < len(rows); i += glyphRows + 1 {
+		code, err := strconv.ParseUint(rows[i], 16, 32)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		var glyph [glyphRows]uint8
+		for j, row := range rows[i+1 : i+1+glyphRows] {
+			if len(row) != glyphCols {
+				log.Fatalf("glyph(%s) row(%s) has wrong width", rows[i], row)
+			}
+			for k, c := range row {
+				if c == '#' {
+					glyph[j] |= 1 << (glyphCols - 1 - k)
+				}
+			}
+		}
+		font[rune(code)] = glyph
+	}
+
+	return font
+}
+
+// PNGRenderer rasterizes treemap into PNG image
+type PNGRenderer struct {
+	Progress treemap.Progress // no progress is reported when not set
+}
+
+// Render renders the treemap into PNG file
+func (r PNGRenderer) Render(root UIBox, w, h float64, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	if err := r.RenderTo(root, w, h, file); err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+// RenderTo renders the treemap as PNG to writer
+func (r PNGRenderer) RenderTo(root UIBox, w, h float64, out io.Writer) error {
+	img, err := r.Rasterize(root, w, h)
+	if err != nil {
+		return err
+	}
+
+	bar := treemap.ProgressOrNop(r.Progress)
+	bar.Start("Encoding PNG tree map", -1)
+	defer bar.Finish()
+
+	if err := png.Encode(out, img); err != nil {
+		return fmt.Errorf("failed to encode png: %w", err)
+	}
+
+	return nil
+}
+
+// Rasterize draws boxes, borders and titles on white image of given size
+func (r PNGRenderer) Rasterize(root UIBox, w, h float64) (*image.RGBA, error) {
+	if !root.IsRoot {
+		return nil, fmt.Errorf("not a root node")
+	}
+
+	bar := treemap.ProgressOrNop(r.Progress)
+	bar.Start("Rasterizing PNG tree map", -1)
+	defer bar.Finish()
+
+	img := image.NewRGBA(image.Rect(0, 0, int(math.Ceil(w)), int(math.Ceil(h))))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	// title of tree map, boxes of root are not drawn
+	if root.Title != nil {
+		drawText(img, root.Title)
+	}
+
+	// parents are drawn before children, same as in SVG
+	que := []UIBox{root}
+	var q UIBox
+	for len(que) > 0 {
+		q, que = que[0], que[1:]
+		que = append(que, q.Children...)
+
+		if q.IsInvisible {
+			continue
+		}
+		bar.Add(1)
+		drawBox(img, q)
+		if q.Title != nil {
+			drawText(img, q.Title)
+		}
+	}
+
+	return img, nil
+}
+
+func drawBox(img *image.RGBA, q UIBox) {
+	rect := image.Rect(
+		int(math.Round(q.X)),
+		int(math.Round(q.Y)),
+		int(math.Round(q.X+q.W)),
+		int(math.Round(q.Y+q.H)),
+	)
+
+	var fill color.Color = color.White
+	if q.Color != nil {
+		fill = q.Color
+	}
+	draw.Draw(img, rect, image.NewUniform(fill), image.Point{}, draw.Over)
+
+	var border color.Color = color.White
+	if q.BorderColor != nil {
+		border = q.BorderColor
+	}
+	b := image.NewUniform(border)
+	for _, line := range []image.Rectangle{
+		image.Rect(rect.Min.X, rect.Min.Y, rect.Max.X, rect.Min.Y+1),
+		image.Rect(rect.Min.X, rect.Max.Y-1, rect.Max.X, rect.Max.Y),
+		image.Rect(rect.Min.X, rect.Min.Y+1, rect.Min.X+1, rect.Max.Y-1),
+		image.Rect(rect.Max.X-1, rect.Min.Y+1, rect.Max.X, rect.Max.Y-1),
+	} {
+		draw.Draw(img, line, b, image.Point{}, draw.Over)
+	}
+}
+
+// drawText draws text with bitmap font scaled with nearest neighbour to same dimensions as in SVG.
+// Text bottom is at Y+H, same as baseline in SVG.
+func drawText(img *image.RGBA, t *UIText) {
+	var c color.Color = DarkTextColor
+	if t.Color != nil {
+		c = t.Color
+	}
+
+	cellW := float64(fontSize) * textWidthMultiplier * t.Scale
+	cellH := t.H * t.Scale
+	if cellW <= 0 || cellH <= 0 {
+		return
+	}
+
+	top := t.Y + t.H - cellH
+	for i, r := range []rune(t.Text) {
+		glyph, ok := font5x7[r]
+		if !ok {
+			glyph = font5x7['?']
+		}
+
+		left := t.X + float64(i)*cellW
+		for py := int(math.Floor(top)); float64(py) < top+cellH; py++ {
+			row := int((float64(py) + 0.5 - top) / cellH * float64(glyphRows))
+			if row < 0 || row >= glyphRows {
+				continue
+			}
+			for px := int(math.Floor(left)); float64(px) < left+cellW; px++ {
+				col := int((float64(px) + 0.5 - left) / cellW * float64(glyphCellCols))
+				if col < 0 || col >= glyphCols {
+					continue
+				}
+				if glyph[row]&(1<<(glyphCols-1-col)) != 0 {
+					img.Set(px, py, c)
+				}
+			}
+		}
+	}
+}
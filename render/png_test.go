@@ -0,0 +1,89 @@
+package render
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestFont5x7(t *testing.T) {
+	if len(font5x7) != 95 {
+		t.Errorf("exp(95) glyphs != got(%d)", len(font5x7))
+	}
+
+	exp := [glyphRows]uint8{0b01110, 0b10001, 0b10001, 0b11111, 0b10001, 0b10001, 0b10001}
+	if got := font5x7['A']; got != exp {
+		t.Errorf("glyph A: exp(%v) != got(%v)", exp, got)
+	}
+}
+
+func TestPNGRendererRasterize(t *testing.T) {
+	root := UIBox{
+		IsRoot:      true,
+		IsInvisible: true,
+		Children: []UIBox{
+			{
+				X: 2, Y: 2, W: 16, H: 16,
+				Color:       color.RGBA{R: 255, A: 255},
+				BorderColor: color.RGBA{B: 255, A: 255},
+			},
+		},
+	}
+
+	img, err := PNGRenderer{}.Rasterize(root, 20, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		x, y     int
+		expColor color.RGBA
+	}{
+		{name: "background", x: 0, y: 0, expColor: color.RGBA{R: 255, G: 255, B: 255, A: 255}},
+		{name: "border", x: 2, y: 10, expColor: color.RGBA{B: 255, A: 255}},
+		{name: "fill", x: 10, y: 10, expColor: color.RGBA{R: 255, A: 255}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if c := img.RGBAAt(tc.x, tc.y); c != tc.expColor {
+				t.Errorf("exp(%#v) != got(%#v)", tc.expColor, c)
+			}
+		})
+	}
+}
+
+func TestPNGRendererRenderToText(t *testing.T) {
+	root := UIBox{
+		IsRoot:      true,
+		IsInvisible: true,
+		Children: []UIBox{
+			{
+				W: 100, H: 40,
+				Color:       color.White,
+				BorderColor: color.White,
+				Title:       &UIText{Text: "|", X: 0, Y: 0, H: 10, Scale: 1, Color: color.Black},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := (PNGRenderer{}).RenderTo(root, 100, 40, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// middle column of bar glyph is set
+	if r, g, b, _ := img.At(4, 5).RGBA(); r != 0 || g != 0 || b != 0 {
+		t.Errorf("expected text pixel to be black, got(%d, %d, %d)", r, g, b)
+	}
+	// spacing column is not set
+	if r, _, _, _ := img.At(9, 5).RGBA(); r == 0 {
+		t.Error("expected spacing pixel to be white")
+	}
+}
@@ -1,10 +1,10 @@
 package render
 
 import (
+	"context"
 	"fmt"
 	"image/color"
 	"strings"
-	"time"
 	"unicode/utf8"
 
 	"github.com/MazenAlkhatib/treemap"
@@ -36,8 +36,20 @@ type UIText struct {
 	Color color.Color
 }
 
+// UINode is information about node rendered in box, such as for tooltips.
+type UINode struct {
+	Path       string // full path
+	Size       float64
+	ParentSize float64 // zero for root of tree
+	RootSize   float64
+	Heat       float64
+	HasHeat    bool
+}
+
 // UIBox is spec on how to render a box. Could be Root.
 type UIBox struct {
+	Path        string // node identifier in tree, not set for root
+	Node        *UINode
 	Title       *UIText
 	X           float64
 	Y           float64
@@ -62,11 +74,55 @@ type Colorer interface {
 type UITreeMapBuilder struct {
 	Colorer     Colorer
 	BorderColor color.Color
+	Layout      layout.Layout    // squarified layout when not set
+	Progress    treemap.Progress // no progress is reported when not set
+
+	// MaxDepth renders nodes at this depth as leaves sized by their whole subtree, root is at depth 1. No limit when zero.
+	MaxDepth int
+	// ShowDescendantCount adds number of descendants to titles of nodes cut off by MaxDepth.
+	ShowDescendantCount bool
+
+	// Title is shown above tree map in padding around root content, such as breadcrumb of focused subtree.
+	// It is not shown when padding is too small for text.
+	Title string
 }
 
 func (s UITreeMapBuilder) NewUITreeMap(tree treemap.Tree, w, h, margin, padding, paddingRoot float64) UIBox {
-	start := time.Now()
-	fmt.Printf("Building UI tree map...\n")
+	t, _ := s.NewUITreeMapContext(context.Background(), tree, w, h, margin, padding, paddingRoot)
+	return t
+}
+
+// NewUITreeMapContext is same as NewUITreeMap, but stops with context error when context is done.
+func (s UITreeMapBuilder) NewUITreeMapContext(ctx context.Context, tree treemap.Tree, w, h, margin, padding, paddingRoot float64) (UIBox, error) {
+	return newUITreeMap[string](ctx, s, mapUITree{tree: tree, colorer: s.Colorer}, w, h, margin, padding, paddingRoot)
+}
+
+// NewUICompactTreeMap is same as NewUITreeMap, but for compact tree.
+// Colorer has to implement CompactColorer, otherwise boxes are not colored.
+func (s UITreeMapBuilder) NewUICompactTreeMap(tree *treemap.CompactTree, w, h, margin, padding, paddingRoot float64) UIBox {
+	t, _ := s.NewUICompactTreeMapContext(context.Background(), tree, w, h, margin, padding, paddingRoot)
+	return t
+}
+
+// NewUICompactTreeMapContext is same as NewUICompactTreeMap, but stops with context error when context is done.
+func (s UITreeMapBuilder) NewUICompactTreeMapContext(ctx context.Context, tree *treemap.CompactTree, w, h, margin, padding, paddingRoot float64) (UIBox, error) {
+	colorer, ok := s.Colorer.(CompactColorer)
+	if !ok {
+		colorer = NoneColorer{}
+	}
+	return newUITreeMap[int32](ctx, s, compactUITree{tree: tree, colorer: colorer}, w, h, margin, padding, paddingRoot)
+}
+
+func (s UITreeMapBuilder) NewUIBox(node string, tree treemap.Tree, x, y, w, h, margin float64, padding float64) UIBox {
+	s.Progress = treemap.ProgressOrNop(s.Progress)
+	t, _ := newUIBox[string](context.Background(), s, mapUITree{tree: tree, colorer: s.Colorer}, node, 1, x, y, w, h, margin, padding)
+	return t
+}
+
+func newUITreeMap[K comparable](ctx context.Context, s UITreeMapBuilder, tree uiTree[K], w, h, margin, padding, paddingRoot float64) (UIBox, error) {
+	s.Progress = treemap.ProgressOrNop(s.Progress)
+	s.Progress.Start("Building UI tree map", -1)
+	defer s.Progress.Finish()
 
 	t := UIBox{
 		X:           0 + paddingRoot,
@@ -77,31 +133,61 @@ func (s UITreeMapBuilder) NewUITreeMap(tree treemap.Tree, w, h, margin, padding,
 		IsRoot:      true,
 	}
 
-	t.Children = []UIBox{
-		s.NewUIBox(tree.Root, tree, t.X, t.Y, t.W, t.H, margin, padding),
+	if s.Title != "" {
+		if scale, th := fitText(s.Title, fontSize, t.W); scale > 0 && th > 0 && th < paddingRoot {
+			t.Title = &UIText{
+				Text:  s.Title,
+				X:     paddingRoot,
+				Y:     (paddingRoot - th) / 2,
+				W:     t.W,
+				H:     th,
+				Scale: scale,
+				Color: DarkTextColor,
+			}
+		}
 	}
 
-	fmt.Printf("UI tree map building completed in %v\n", time.Since(start))
-	return t
+	box, err := newUIBox(ctx, s, tree, tree.root(), 1, t.X, t.Y, t.W, t.H, margin, padding)
+	if err != nil {
+		return UIBox{}, err
+	}
+	t.Children = []UIBox{box}
+
+	return t, nil
 }
 
-func (s UITreeMapBuilder) NewUIBox(node string, tree treemap.Tree, x, y, w, h, margin float64, padding float64) UIBox {
+func newUIBox[K comparable](ctx context.Context, s UITreeMapBuilder, tree uiTree[K], node K, depth int, x, y, w, h, margin float64, padding float64) (UIBox, error) {
 	if (w <= (2 * padding)) || (h <= (2 * padding)) || w < tooSmallBoxWidth || h < tooSmallBoxHeight {
 		// too small, do not render
-		return UIBox{}
+		return UIBox{}, nil
 	}
+	if err := ctx.Err(); err != nil {
+		return UIBox{}, err
+	}
+
+	s.Progress.Add(1)
 
+	info := tree.node(node)
 	t := UIBox{
+		Path:        tree.key(node),
+		Node:        &info,
 		X:           x + margin,
 		Y:           y + margin,
 		W:           w - (2 * margin),
 		H:           h - (2 * margin),
-		Color:       s.Colorer.ColorBox(tree, node),
+		Color:       tree.colorBox(node),
 		BorderColor: s.BorderColor,
 	}
 
+	children := tree.children(node)
+	isCutOff := s.MaxDepth > 0 && depth >= s.MaxDepth && len(children) > 0
+
 	var textHeight float64
-	if title := entityToSlash.Replace(tree.Nodes[node].Name); title != "" && title != "some-secret-string" {
+	if title := tree.name(node); title != "" {
+		if isCutOff && s.ShowDescendantCount {
+			title = fmt.Sprintf("%s (%d)", title, countDescendants(tree, node))
+		}
+
 		// fit text
 		// margin here and padding to account for children
 		w := t.W - (2 * padding) - (2 * margin)
@@ -116,18 +202,18 @@ func (s UITreeMapBuilder) NewUIBox(node string, tree treemap.Tree, x, y, w, h, m
 				W:     w,
 				H:     textHeight,
 				Scale: scale,
-				Color: s.Colorer.ColorText(tree, node),
+				Color: tree.colorText(node),
 			}
 		}
 	}
 
-	if len(tree.To[node]) == 0 {
-		return t
+	if len(children) == 0 || isCutOff {
+		return t, nil
 	}
 
-	areas := make([]float64, 0, len(tree.To[node]))
-	for _, toPath := range tree.To[node] {
-		areas = append(areas, nodeSize(tree, toPath))
+	areas := make([]float64, 0, len(children))
+	for _, child := range children {
+		areas = append(areas, tree.size(child))
 	}
 
 	childrenContainer := layout.Box{
@@ -136,16 +222,24 @@ func (s UITreeMapBuilder) NewUIBox(node string, tree treemap.Tree, x, y, w, h, m
 		W: t.W - (2 * padding),
 		H: t.H - (2 * padding) - textHeight - (2 * textMarginH),
 	}
-	boxes := layout.Squarify(childrenContainer, areas)
+	var boxes []layout.Box
+	if s.Layout != nil {
+		boxes = s.Layout.Layout(childrenContainer, areas)
+	} else {
+		boxes = layout.Squarify(childrenContainer, areas)
+	}
 
-	for i, toPath := range tree.To[node] {
+	for i, child := range children {
 		if boxes[i] == layout.NilBox {
 			continue
 		}
 
-		box := s.NewUIBox(
-			toPath,
+		box, err := newUIBox(
+			ctx,
+			s,
 			tree,
+			child,
+			depth+1,
 			boxes[i].X,
 			boxes[i].Y,
 			boxes[i].W,
@@ -153,13 +247,25 @@ func (s UITreeMapBuilder) NewUIBox(node string, tree treemap.Tree, x, y, w, h, m
 			margin,
 			padding,
 		)
+		if err != nil {
+			return UIBox{}, err
+		}
 		if box.IsEmpty() {
 			continue
 		}
+		box.Node.ParentSize = t.Node.Size
 		t.Children = append(t.Children, box)
 	}
 
-	return t
+	return t, nil
+}
+
+func countDescendants[K comparable](tree uiTree[K], node K) int {
+	var n int
+	for _, child := range tree.children(node) {
+		n += 1 + countDescendants(tree, child)
+	}
+	return n
 }
 
 func nodeSize(tree treemap.Tree, node string) float64 {
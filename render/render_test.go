@@ -1,8 +1,13 @@
 package render
 
 import (
+	"context"
+	"errors"
 	"math"
+	"reflect"
 	"testing"
+
+	"github.com/MazenAlkhatib/treemap"
 )
 
 func TestTextWidth(t *testing.T) {
@@ -28,3 +33,149 @@ func TestTextWidth(t *testing.T) {
 		})
 	}
 }
+
+func TestNewUITreeMapNodeInfo(t *testing.T) {
+	tree := treemap.Tree{
+		Nodes: map[string]treemap.Node{
+			"a":   {Path: "a", Name: "a", Size: 4},
+			"a/b": {Path: "a/b", Name: "b", Size: 1, Heat: 3, HasHeat: true},
+			"a/c": {Path: "a/c", Name: "c", Size: 3},
+		},
+		To: map[string][]string{
+			"a": {"a/b", "a/c"},
+		},
+		Root: "a",
+	}
+
+	root := UITreeMapBuilder{Colorer: NoneColorer{}}.NewUITreeMap(tree, 200, 200, 1, 1, 0)
+
+	a := root.Children[0]
+	if exp := (UINode{Path: "a", Size: 4, RootSize: 4}); *a.Node != exp {
+		t.Errorf("exp(%#v) != got(%#v)", exp, *a.Node)
+	}
+	if len(a.Children) != 2 {
+		t.Fatalf("exp(2) children != got(%d)", len(a.Children))
+	}
+	if exp := (UINode{Path: "a/b", Size: 1, ParentSize: 4, RootSize: 4, Heat: 3, HasHeat: true}); *a.Children[0].Node != exp {
+		t.Errorf("exp(%#v) != got(%#v)", exp, *a.Children[0].Node)
+	}
+}
+
+func TestNewUICompactTreeMapSameAsNewUITreeMap(t *testing.T) {
+	b := treemap.NewCompactTreeBuilder()
+	for _, n := range []treemap.Node{
+		{Path: "a/b/c", Size: 5, Heat: 1, HasHeat: true},
+		{Path: "a/b/d", Size: 2},
+		{Path: "a/e", Size: 7},
+		{Path: "a/f&sol;g", Size: 1},
+	} {
+		b.Add(n)
+	}
+	compact := b.Build()
+	treemap.SumSizeImputer{EmptyLeafSize: 1}.ImputeSizeCompact(compact)
+	tree := compact.Tree()
+
+	builder := UITreeMapBuilder{Colorer: NoneColorer{}}
+	exp := builder.NewUITreeMap(*tree, 400, 300, 2, 2, 4)
+	got := builder.NewUICompactTreeMap(compact, 400, 300, 2, 2, 4)
+
+	if !reflect.DeepEqual(exp, got) {
+		t.Errorf("exp(%#v) != got(%#v)", exp, got)
+	}
+}
+
+func TestNewUITreeMapContextCanceled(t *testing.T) {
+	tree := treemap.Tree{
+		Nodes: map[string]treemap.Node{"a": {Path: "a", Name: "a", Size: 1}},
+		Root:  "a",
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := (UITreeMapBuilder{Colorer: NoneColorer{}}).NewUITreeMapContext(ctx, tree, 200, 200, 1, 1, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("exp(%v) != got(%v)", context.Canceled, err)
+	}
+}
+
+func TestNewUITreeMapMaxDepth(t *testing.T) {
+	tree := treemap.Tree{
+		Nodes: map[string]treemap.Node{
+			"a":       {Path: "a", Name: "a", Size: 4},
+			"a/b":     {Path: "a/b", Name: "b", Size: 3},
+			"a/b/c":   {Path: "a/b/c", Name: "c", Size: 2},
+			"a/b/c/d": {Path: "a/b/c/d", Name: "d", Size: 2},
+			"a/b/e":   {Path: "a/b/e", Name: "e", Size: 1},
+			"a/f":     {Path: "a/f", Name: "f", Size: 1},
+		},
+		To: map[string][]string{
+			"a":     {"a/b", "a/f"},
+			"a/b":   {"a/b/c", "a/b/e"},
+			"a/b/c": {"a/b/c/d"},
+		},
+		Root: "a",
+	}
+
+	root := UITreeMapBuilder{Colorer: NoneColorer{}, MaxDepth: 2, ShowDescendantCount: true}.NewUITreeMap(tree, 400, 400, 1, 1, 0)
+
+	a := root.Children[0]
+	if len(a.Children) != 2 {
+		t.Fatalf("exp(2) children != got(%d)", len(a.Children))
+	}
+	b, f := a.Children[0], a.Children[1]
+	if len(b.Children) != 0 {
+		t.Errorf("exp node at max depth to be leaf, got %d children", len(b.Children))
+	}
+	if b.Node.Size != 3 {
+		t.Errorf("exp(3) size of subtree != got(%v)", b.Node.Size)
+	}
+	if b.Title == nil || b.Title.Text != "b (3)" {
+		t.Errorf("exp(b (3)) title != got(%#v)", b.Title)
+	}
+	if f.Title == nil || f.Title.Text != "f" {
+		t.Errorf("exp(f) title of leaf != got(%#v)", f.Title)
+	}
+}
+
+func TestNewUITreeMapTitle(t *testing.T) {
+	tree := treemap.Tree{
+		Nodes: map[string]treemap.Node{"a": {Path: "a", Name: "a", Size: 1}},
+		Root:  "a",
+	}
+
+	builder := UITreeMapBuilder{Colorer: NoneColorer{}, Title: "x (2) / a (1)"}
+
+	root := builder.NewUITreeMap(tree, 200, 200, 1, 1, 32)
+	if root.Title == nil || root.Title.Text != "x (2) / a (1)" {
+		t.Errorf("exp title, got %#v", root.Title)
+	}
+	if root.Title != nil && root.Title.Y+root.Title.H > 32 {
+		t.Errorf("exp title in padding, got %#v", root.Title)
+	}
+
+	if root := builder.NewUITreeMap(tree, 200, 200, 1, 1, 4); root.Title != nil {
+		t.Errorf("exp no title when padding is too small, got %#v", root.Title)
+	}
+}
+
+func TestNewUITreeMapUnescapesNames(t *testing.T) {
+	tree := treemap.Tree{
+		Nodes: map[string]treemap.Node{
+			"a":      {Path: "a", Name: "a", Size: 1},
+			`a.b\.c`: {Path: `a.b\.c`, Name: `b\.c`, Size: 1},
+		},
+		To:     map[string][]string{"a": {`a.b\.c`}},
+		Root:   "a",
+		Format: treemap.PathFormat{Separator: ".", Escape: '\\'},
+	}
+
+	root := UITreeMapBuilder{Colorer: NoneColorer{}}.NewUITreeMap(tree, 400, 400, 1, 1, 0)
+
+	b := root.Children[0].Children[0]
+	if b.Title == nil || b.Title.Text != "b.c" {
+		t.Errorf("exp(b.c) title != got(%#v)", b.Title)
+	}
+	if b.Node.Path != "a.b.c" {
+		t.Errorf("exp(a.b.c) path != got(%s)", b.Node.Path)
+	}
+}
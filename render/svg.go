@@ -1,13 +1,18 @@
 package render
 
 import (
+	"bufio"
+	"context"
 	"fmt"
 	"image/color"
 	"io"
+	"math"
 	"os"
 	"runtime"
+	"strconv"
 	"strings"
-	"time"
+
+	"github.com/MazenAlkhatib/treemap"
 )
 
 var xmlEscaper = strings.NewReplacer(
@@ -18,35 +23,69 @@ var xmlEscaper = strings.NewReplacer(
 	"'", "&apos;",
 )
 
-// StreamingSVGRenderer is an optimized renderer that writes SVG directly to file
-type StreamingSVGRenderer struct{}
+// StreamingSVGRenderer is an optimized renderer that writes SVG directly to file or any io.Writer
+type StreamingSVGRenderer struct {
+	// PresentationAttributes makes SVG without inline style attributes, by using attributes such as fill and stroke.
+	// Such SVG is rendered under strict Content Security Policy, such as "style-src 'self'" in Jenkins.
+	PresentationAttributes bool
+
+	Progress treemap.Progress // no progress is reported when not set
+}
 
 // RenderStream renders the treemap directly to a file with optimized memory usage
 func (r StreamingSVGRenderer) RenderStream(root UIBox, w, h float64, filename string) error {
+	return r.RenderStreamContext(context.Background(), root, w, h, filename)
+}
+
+// RenderStreamContext is same as RenderStream, but stops with context error when context is done.
+// File is left incomplete then.
+func (r StreamingSVGRenderer) RenderStreamContext(ctx context.Context, root UIBox, w, h float64, filename string) error {
 	if !root.IsRoot {
 		return fmt.Errorf("not a root node")
 	}
 
-	start := time.Now()
-	fmt.Printf("Rendering SVG tree map...\n")
-
 	file, err := os.Create(filename)
 	if err != nil {
 		return fmt.Errorf("failed to create file: %w", err)
 	}
 	defer file.Close()
 
+	if err := r.RenderStreamToContext(ctx, root, w, h, file); err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+// RenderStreamTo renders the treemap to writer, such as os.Stdout, HTTP response or buffer.
+// Writes are buffered, writer does not need to be buffered.
+func (r StreamingSVGRenderer) RenderStreamTo(root UIBox, w, h float64, out io.Writer) error {
+	return r.RenderStreamToContext(context.Background(), root, w, h, out)
+}
+
+// RenderStreamToContext is same as RenderStreamTo, but stops with context error when context is done.
+// Context is checked between batches of boxes, output is incomplete then.
+func (r StreamingSVGRenderer) RenderStreamToContext(ctx context.Context, root UIBox, w, h float64, out io.Writer) error {
+	if !root.IsRoot {
+		return fmt.Errorf("not a root node")
+	}
+
+	bar := treemap.ProgressOrNop(r.Progress)
+	bar.Start("Rendering SVG tree map", -1)
+	defer bar.Finish()
+
+	buf := bufio.NewWriter(out)
+
 	// Write SVG header
-	if _, err := fmt.Fprintf(file, `
-<svg 
-	xmlns="http://www.w3.org/2000/svg" 
-	xmlns:xlink="http://www.w3.org/1999/xlink" 
-	viewBox="0 0 %f %f" 
-	style="background: white none repeat scroll 0%% 0%%;"
->`, w, h); err != nil {
+	if err := streamHeaderSVG(buf, w, h, r.PresentationAttributes); err != nil {
 		return fmt.Errorf("failed to write header: %w", err)
 	}
 
+	// Write title of tree map, boxes of root are not rendered
+	if err := streamTextSVG(buf, root.Title, r.PresentationAttributes); err != nil {
+		return fmt.Errorf("failed to write title: %w", err)
+	}
+
 	// Process boxes in batches to control memory usage
 	const batchSize = 1000
 	que := make([]UIBox, 0, batchSize)
@@ -56,6 +95,10 @@ func (r StreamingSVGRenderer) RenderStream(root UIBox, w, h float64, filename st
 	var currentBatch []UIBox
 
 	for len(que) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		// Take up to batchSize boxes from queue
 		batchEnd := batchSize
 		if batchEnd > len(que) {
@@ -71,9 +114,9 @@ func (r StreamingSVGRenderer) RenderStream(root UIBox, w, h float64, filename st
 				que = append(que, q.Children...)
 			}
 
-			// Write box SVG directly to file
+			// Write box SVG to buffer
 			if !q.IsInvisible {
-				if err := streamBoxSVG(file, q); err != nil {
+				if err := streamBoxSVG(buf, q, r.PresentationAttributes); err != nil {
 					return fmt.Errorf("failed to write box: %w", err)
 				}
 			}
@@ -85,6 +128,8 @@ func (r StreamingSVGRenderer) RenderStream(root UIBox, w, h float64, filename st
 		// Clear batch slice
 		currentBatch = currentBatch[:0]
 
+		bar.Add(batchEnd)
+
 		// Periodic GC
 		processed += batchEnd
 		if processed%10000 == 0 {
@@ -93,16 +138,43 @@ func (r StreamingSVGRenderer) RenderStream(root UIBox, w, h float64, filename st
 	}
 
 	// Write SVG footer
-	if _, err := io.WriteString(file, "\n</svg>"); err != nil {
+	if _, err := io.WriteString(buf, "\n</svg>"); err != nil {
 		return fmt.Errorf("failed to write footer: %w", err)
 	}
 
-	fmt.Printf("SVG tree map rendering completed in %v\n", time.Since(start))
+	if err := buf.Flush(); err != nil {
+		return fmt.Errorf("failed to flush: %w", err)
+	}
+
 	return nil
 }
 
-// streamBoxSVG writes a single box's SVG directly to the file
-func streamBoxSVG(file *os.File, q UIBox) error {
+// streamHeaderSVG writes opening of SVG with white background
+func streamHeaderSVG(out io.Writer, w, h float64, attrs bool) error {
+	if attrs {
+		_, err := fmt.Fprintf(out, `
+<svg 
+	xmlns="http://www.w3.org/2000/svg" 
+	xmlns:xlink="http://www.w3.org/1999/xlink" 
+	viewBox="0 0 %f %f" 
+>
+<rect x="0" y="0" width="%f" height="%f" fill="white" />`, w, h, w, h)
+		return err
+	}
+
+	_, err := fmt.Fprintf(out, `
+<svg 
+	xmlns="http://www.w3.org/2000/svg" 
+	xmlns:xlink="http://www.w3.org/1999/xlink" 
+	viewBox="0 0 %f %f" 
+	style="background: white none repeat scroll 0%% 0%%;"
+>`, w, h)
+	return err
+}
+
+// streamBoxSVG writes a single box's SVG directly to the writer.
+// When attrs is set, then uses presentation attributes instead of style attribute.
+func streamBoxSVG(out io.Writer, q UIBox, attrs bool) error {
 	// Get box colors
 	r, g, b, a := color.White.RGBA()
 	if q.Color != color.Opaque {
@@ -123,13 +195,18 @@ func streamBoxSVG(file *os.File, q UIBox) error {
 	bo := float64(ba>>8) / 255.0
 
 	// Write box opening
-	if _, err := io.WriteString(file, "\n<g>"); err != nil {
+	if err := streamBoxOpeningSVG(out, q.Node); err != nil {
 		return err
 	}
 
 	// Write rectangle
-	if _, err := fmt.Fprintf(file, `
-	<rect x="%f" y="%f" width="%f" height="%f" style="fill: rgb(%d, %d, %d);opacity:1;fill-opacity:%.2f;stroke:rgb(%d,%d,%d);stroke-width:1px;stroke-opacity:%.2f;" />`,
+	rectFormat := `
+	<rect x="%f" y="%f" width="%f" height="%f" style="fill: rgb(%d, %d, %d);opacity:1;fill-opacity:%.2f;stroke:rgb(%d,%d,%d);stroke-width:1px;stroke-opacity:%.2f;" />`
+	if attrs {
+		rectFormat = `
+	<rect x="%f" y="%f" width="%f" height="%f" fill="rgb(%d,%d,%d)" opacity="1" fill-opacity="%.2f" stroke="rgb(%d,%d,%d)" stroke-width="1" stroke-opacity="%.2f" />`
+	}
+	if _, err := fmt.Fprintf(out, rectFormat,
 		q.X, q.Y, q.W, q.H,
 		r, g, b, o,
 		br, bg, bb, bo); err != nil {
@@ -138,21 +215,74 @@ func streamBoxSVG(file *os.File, q UIBox) error {
 
 	// Write text if present
 	if q.Title != nil {
-		if err := streamTextSVG(file, q.Title); err != nil {
+		if err := streamTextSVG(out, q.Title, attrs); err != nil {
 			return err
 		}
 	}
 
 	// Write box closing
-	if _, err := io.WriteString(file, "\n</g>\n"); err != nil {
+	if _, err := io.WriteString(out, "\n</g>\n"); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-// streamTextSVG writes text SVG directly to the file
-func streamTextSVG(file *os.File, t *UIText) error {
+// streamBoxOpeningSVG writes group with node metadata and tooltip
+func streamBoxOpeningSVG(out io.Writer, n *UINode) error {
+	if n == nil {
+		_, err := io.WriteString(out, "\n<g>")
+		return err
+	}
+
+	heatAttr := ""
+	if n.HasHeat {
+		heatAttr = fmt.Sprintf(` data-heat="%g"`, n.Heat)
+	}
+
+	_, err := fmt.Fprintf(out, `
+<g data-path="%s" data-size="%g"%s>
+	<title>%s</title>`,
+		xmlEscaper.Replace(n.Path),
+		n.Size,
+		heatAttr,
+		xmlEscaper.Replace(tooltipText(n)))
+
+	return err
+}
+
+// tooltipText describes node in multiple lines
+func tooltipText(n *UINode) string {
+	var b strings.Builder
+	b.WriteString(n.Path)
+
+	fmt.Fprintf(&b, "\nsize: %s", HumanizeSize(n.Size))
+	if n.ParentSize > 0 {
+		fmt.Fprintf(&b, "\nshare of parent: %.2f%%", 100*n.Size/n.ParentSize)
+	}
+	if n.RootSize > 0 {
+		fmt.Fprintf(&b, "\nshare of root: %.2f%%", 100*n.Size/n.RootSize)
+	}
+	if n.HasHeat {
+		fmt.Fprintf(&b, "\nheat: %g", n.Heat)
+	}
+
+	return b.String()
+}
+
+// HumanizeSize formats size with SI suffix, such as 1.5k or 12.3M
+func HumanizeSize(v float64) string {
+	units := []string{"", "k", "M", "G", "T", "P"}
+	i := 0
+	for math.Abs(v) >= 1000 && i < len(units)-1 {
+		v /= 1000
+		i++
+	}
+	return strconv.FormatFloat(math.Round(v*10)/10, 'f', -1, 64) + units[i]
+}
+
+// streamTextSVG writes text SVG directly to the writer
+func streamTextSVG(out io.Writer, t *UIText, attrs bool) error {
 	if t == nil {
 		return nil
 	}
@@ -166,13 +296,28 @@ func streamTextSVG(file *os.File, t *UIText) error {
 	b = b >> 8
 	o := float64(a>>8) / 255.0
 
-	_, err := fmt.Fprintf(file, `
+	textFormat := `
 	<text 
 		data-notex="1" 
 		text-anchor="start"
 		transform="translate(%f,%f) scale(%f)"
 		style="font-family: Open Sans, verdana, arial, sans-serif !important; font-size: %dpx; fill: rgb(%d, %d, %d); fill-opacity: %.2f; white-space: pre;" 
-		data-math="N">%s</text>`,
+		data-math="N">%s</text>`
+	if attrs {
+		textFormat = `
+	<text 
+		data-notex="1" 
+		text-anchor="start"
+		transform="translate(%f,%f) scale(%f)"
+		font-family="Open Sans, verdana, arial, sans-serif"
+		font-size="%dpx"
+		fill="rgb(%d,%d,%d)"
+		fill-opacity="%.2f"
+		xml:space="preserve"
+		data-math="N">%s</text>`
+	}
+
+	_, err := fmt.Fprintf(out, textFormat,
 		t.X,
 		t.Y+t.H,
 		t.Scale,
@@ -0,0 +1,171 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestStreamingSVGRendererRenderStreamTo(t *testing.T) {
+	root := UIBox{
+		IsRoot:      true,
+		IsInvisible: true,
+		Children: []UIBox{
+			{
+				X: 1, Y: 2, W: 3, H: 4,
+				Color:       color.White,
+				BorderColor: color.Black,
+				Title:       &UIText{Text: "a & b", Scale: 1, Color: color.Black},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := (StreamingSVGRenderer{}).RenderStreamTo(root, 10, 10, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, exp := range []string{
+		`viewBox="0 0 10.000000 10.000000"`,
+		`<rect x="1.000000" y="2.000000" width="3.000000" height="4.000000"`,
+		`>a &amp; b</text>`,
+	} {
+		if !strings.Contains(out, exp) {
+			t.Errorf("output does not contain %q", exp)
+		}
+	}
+	if !strings.HasSuffix(out, "</svg>") {
+		t.Error("output is not flushed till the end")
+	}
+}
+
+func TestStreamingSVGRendererNotRoot(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (StreamingSVGRenderer{}).RenderStreamTo(UIBox{}, 10, 10, &buf); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestStreamingSVGRendererContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var buf bytes.Buffer
+	root := UIBox{IsRoot: true, IsInvisible: true}
+	if err := (StreamingSVGRenderer{}).RenderStreamToContext(ctx, root, 10, 10, &buf); !errors.Is(err, context.Canceled) {
+		t.Errorf("exp(%v) != got(%v)", context.Canceled, err)
+	}
+}
+
+func TestStreamingSVGRendererTitle(t *testing.T) {
+	root := UIBox{
+		IsRoot:      true,
+		IsInvisible: true,
+		Title:       &UIText{Text: "a (10) / b (3)", Scale: 1, Color: DarkTextColor},
+	}
+
+	var buf bytes.Buffer
+	if err := (StreamingSVGRenderer{}).RenderStreamTo(root, 10, 10, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := ">a (10) / b (3)</text>"; !strings.Contains(buf.String(), exp) {
+		t.Errorf("output does not contain %q", exp)
+	}
+}
+
+func TestStreamingSVGRendererTooltip(t *testing.T) {
+	root := UIBox{
+		IsRoot:      true,
+		IsInvisible: true,
+		Children: []UIBox{
+			{
+				W: 3, H: 4,
+				Color:       color.White,
+				BorderColor: color.Black,
+				Node: &UINode{
+					Path:       "a/<b>",
+					Size:       1500,
+					ParentSize: 3000,
+					RootSize:   6000,
+					Heat:       0.5,
+					HasHeat:    true,
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := (StreamingSVGRenderer{}).RenderStreamTo(root, 10, 10, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, exp := range []string{
+		`<g data-path="a/&lt;b&gt;" data-size="1500" data-heat="0.5">`,
+		"<title>a/&lt;b&gt;\nsize: 1.5k\nshare of parent: 50.00%\nshare of root: 25.00%\nheat: 0.5</title>",
+	} {
+		if !strings.Contains(out, exp) {
+			t.Errorf("output does not contain %q", exp)
+		}
+	}
+}
+
+func TestHumanizeSize(t *testing.T) {
+	tests := []struct {
+		v   float64
+		exp string
+	}{
+		{v: 0, exp: "0"},
+		{v: 999, exp: "999"},
+		{v: 1000, exp: "1k"},
+		{v: 33333216, exp: "33.3M"},
+		{v: 0.25, exp: "0.3"},
+		{v: -2500, exp: "-2.5k"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.exp, func(t *testing.T) {
+			if got := HumanizeSize(tc.v); got != tc.exp {
+				t.Errorf("exp(%s) != got(%s)", tc.exp, got)
+			}
+		})
+	}
+}
+
+func TestStreamingSVGRendererPresentationAttributes(t *testing.T) {
+	root := UIBox{
+		IsRoot:      true,
+		IsInvisible: true,
+		Children: []UIBox{
+			{
+				W: 3, H: 4,
+				Color:       color.RGBA{R: 255, A: 255},
+				BorderColor: color.White,
+				Title:       &UIText{Text: "a", Scale: 1, Color: color.Black},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := (StreamingSVGRenderer{PresentationAttributes: true}).RenderStreamTo(root, 10, 10, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "style=") || strings.Contains(out, "<style") {
+		t.Error("output has styles")
+	}
+	for _, exp := range []string{
+		`<rect x="0" y="0" width="10.000000" height="10.000000" fill="white" />`,
+		`fill="rgb(255,0,0)" opacity="1" fill-opacity="1.00" stroke="rgb(255,255,255)"`,
+		`font-size="12px"`,
+	} {
+		if !strings.Contains(out, exp) {
+			t.Errorf("output does not contain %q", exp)
+		}
+	}
+}
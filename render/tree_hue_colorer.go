@@ -4,8 +4,8 @@ import (
 	"image/color"
 	"math"
 
-	"github.com/lucasb-eyer/go-colorful"
 	"github.com/MazenAlkhatib/treemap"
+	"github.com/lucasb-eyer/go-colorful"
 )
 
 // TreeHueColorer this algorithm will split Hue in NCL ranges such that deeper nodes have more specific hue.
@@ -14,13 +14,14 @@ import (
 // The challenge that not all HCL values are valid colors. Which is why we have to sample and look for value within range.
 // For very deep trees, that require precise colors colors closer to leaves will get mixed due to sampling.
 type TreeHueColorer struct {
-	Hues   map[string]float64 // memoized hues
-	C      float64            // will be in all colors
-	L      float64            // will be in all colors
-	Offset float64            // 0 ~ 360 hue offset in HCL for tree
-	DeltaH float64            // tolerance for approximate color
-	DeltaC float64            // tolerance for approximate color
-	DeltaL float64            // tolerance for approximate color
+	Hues        map[string]float64 // memoized hues
+	CompactHues map[int32]float64  // memoized hues of compact tree, by node
+	C           float64            // will be in all colors
+	L           float64            // will be in all colors
+	Offset      float64            // 0 ~ 360 hue offset in HCL for tree
+	DeltaH      float64            // tolerance for approximate color
+	DeltaC      float64            // tolerance for approximate color
+	DeltaL      float64            // tolerance for approximate color
 }
 
 func (s TreeHueColorer) ColorBox(tree treemap.Tree, node string) color.Color {
@@ -29,11 +30,31 @@ func (s TreeHueColorer) ColorBox(tree treemap.Tree, node string) color.Color {
 			s.Hues[k] = v
 		}
 	}
+	return s.hueColor(s.Hues[node])
+}
 
+func (s TreeHueColorer) ColorText(tree treemap.Tree, node string) color.Color {
+	return s.textColor(s.ColorBox(tree, node))
+}
+
+func (s TreeHueColorer) ColorBoxCompact(tree *treemap.CompactTree, node int32) color.Color {
+	if len(s.CompactHues) == 0 {
+		for k, v := range TreeHuesCompact(tree, s.Offset) {
+			s.CompactHues[k] = v
+		}
+	}
+	return s.hueColor(s.CompactHues[node])
+}
+
+func (s TreeHueColorer) ColorTextCompact(tree *treemap.CompactTree, node int32) color.Color {
+	return s.textColor(s.ColorBoxCompact(tree, node))
+}
+
+func (s TreeHueColorer) hueColor(hue float64) color.Color {
 	// some of HCL is not valid. using generator from go-colorful package to get one colour
 	f := func(l, a, b float64) bool {
 		// target
-		th, tc, tl := s.Hues[node], s.C, s.L
+		th, tc, tl := hue, s.C, s.L
 		// current
 		h, c, l := colorful.LabToHcl(l, a, b)
 		// withing range
@@ -48,8 +69,8 @@ func (s TreeHueColorer) ColorBox(tree treemap.Tree, node string) color.Color {
 	return palette[0]
 }
 
-func (s TreeHueColorer) ColorText(tree treemap.Tree, node string) color.Color {
-	boxColor := s.ColorBox(tree, node).(colorful.Color)
+func (s TreeHueColorer) textColor(box color.Color) color.Color {
+	boxColor := box.(colorful.Color)
 	_, _, l := boxColor.Hcl()
 	switch {
 	case l > 0.5:
@@ -113,3 +134,35 @@ func TreeHues(tree treemap.Tree, offset float64) map[string]float64 {
 
 	return hues
 }
+
+// TreeHuesCompact is same as TreeHues, but for compact tree.
+func TreeHuesCompact(tree *treemap.CompactTree, offset float64) map[int32]float64 {
+	ranges := map[int32][2]float64{tree.Root: {offset, 360 + offset}}
+
+	que := []int32{tree.Root}
+	var q int32
+	for len(que) > 0 {
+		q, que = que[0], que[1:]
+		children := tree.ChildrenOf(q)
+		que = append(que, children...)
+
+		// for N children we allocating N parts of parent's range, single child has same color as parent
+		minH, maxH := ranges[q][0], ranges[q][1]
+		split := minH
+		w := math.Abs(maxH-minH) / float64(len(children))
+		for i, child := range children {
+			if i == (len(children) - 1) {
+				ranges[child] = [2]float64{split, maxH}
+				continue
+			}
+			ranges[child] = [2]float64{split, split + w}
+			split += w
+		}
+	}
+
+	hues := make(map[int32]float64, len(ranges))
+	for node, r := range ranges {
+		hues[node] = math.Mod(((r[0] + r[1]) / 2), 360)
+	}
+	return hues
+}
@@ -65,3 +65,31 @@ func TestTreeHues(t *testing.T) {
 		})
 	}
 }
+
+func TestTreeHuesCompact(t *testing.T) {
+	b := treemap.NewCompactTreeBuilder()
+	for _, path := range []string{"a/b/c", "a/b/d", "a/e", "a/f/g"} {
+		b.Add(treemap.Node{Path: path, Size: 1})
+	}
+	tree := b.Build()
+
+	expHues := TreeHues(*tree.Tree(), 30)
+	hues := TreeHuesCompact(tree, 30)
+	for node, hue := range hues {
+		if exp := expHues[tree.Path(node)]; exp != hue {
+			t.Errorf("%s: exp(%v) != got(%v)", tree.Path(node), exp, hue)
+		}
+	}
+	if len(hues) != len(expHues) {
+		t.Errorf("len: exp(%d) != got(%d)", len(expHues), len(hues))
+	}
+
+	colorer := TreeHueColorer{CompactHues: map[int32]float64{}, C: 0.5, L: 0.5, DeltaH: 10, DeltaC: 0.3, DeltaL: 0.1}
+	var c CompactColorer = colorer
+	if box := c.ColorBoxCompact(tree, tree.Root); box == nil {
+		t.Errorf("no color of box")
+	}
+	if len(colorer.CompactHues) != len(hues) {
+		t.Errorf("hues are not memoized")
+	}
+}
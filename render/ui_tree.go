@@ -0,0 +1,102 @@
+package render
+
+import (
+	"image/color"
+
+	"github.com/MazenAlkhatib/treemap"
+)
+
+// CompactColorer colors nodes of compact tree.
+type CompactColorer interface {
+	ColorBoxCompact(tree *treemap.CompactTree, node int32) color.Color
+	ColorTextCompact(tree *treemap.CompactTree, node int32) color.Color
+}
+
+// uiTree is what UITreeMapBuilder needs from tree, so that different tree representations can be rendered.
+type uiTree[K comparable] interface {
+	root() K
+	key(node K) string  // node identifier in UIBox
+	name(node K) string // title of box, empty when should not be rendered
+	node(node K) UINode // without parent size
+	size(node K) float64
+	children(node K) []K
+	colorBox(node K) color.Color
+	colorText(node K) color.Color
+}
+
+type mapUITree struct {
+	tree    treemap.Tree
+	colorer Colorer
+}
+
+func (t mapUITree) root() string { return t.tree.Root }
+
+func (t mapUITree) key(node string) string { return node }
+
+func (t mapUITree) name(node string) string {
+	name := entityToSlash.Replace(t.tree.Format.Unescape(t.tree.Nodes[node].Name))
+	if name == "some-secret-string" {
+		return ""
+	}
+	return name
+}
+
+func (t mapUITree) node(node string) UINode {
+	n := t.tree.Nodes[node]
+	info := UINode{
+		Path:     entityToSlash.Replace(t.tree.Format.Unescape(n.Path)),
+		Size:     nodeSize(t.tree, node),
+		RootSize: nodeSize(t.tree, t.tree.Root),
+		Heat:     n.Heat,
+		HasHeat:  n.HasHeat,
+	}
+	if node == "some-secret-string" {
+		info.Path = ""
+	}
+	return info
+}
+
+func (t mapUITree) size(node string) float64 { return nodeSize(t.tree, node) }
+
+func (t mapUITree) children(node string) []string { return t.tree.To[node] }
+
+func (t mapUITree) colorBox(node string) color.Color { return t.colorer.ColorBox(t.tree, node) }
+
+func (t mapUITree) colorText(node string) color.Color { return t.colorer.ColorText(t.tree, node) }
+
+// compactUITree reconstructs paths only for nodes that have boxes
+type compactUITree struct {
+	tree    *treemap.CompactTree
+	colorer CompactColorer
+}
+
+func (t compactUITree) root() int32 { return t.tree.Root }
+
+func (t compactUITree) key(node int32) string { return t.tree.Path(node) }
+
+func (t compactUITree) name(node int32) string {
+	return entityToSlash.Replace(t.tree.Format.Unescape(t.tree.Name(node)))
+}
+
+func (t compactUITree) node(node int32) UINode {
+	n := t.tree.Nodes[node]
+	return UINode{
+		Path:     entityToSlash.Replace(t.tree.Format.Unescape(t.tree.Path(node))),
+		Size:     n.Size,
+		RootSize: t.tree.Nodes[t.tree.Root].Size,
+		Heat:     n.Heat,
+		HasHeat:  n.HasHeat,
+	}
+}
+
+func (t compactUITree) size(node int32) float64 { return t.tree.Nodes[node].Size }
+
+func (t compactUITree) children(node int32) []int32 { return t.tree.ChildrenOf(node) }
+
+func (t compactUITree) colorBox(node int32) color.Color {
+	return t.colorer.ColorBoxCompact(t.tree, node)
+}
+
+func (t compactUITree) colorText(node int32) color.Color {
+	return t.colorer.ColorTextCompact(t.tree, node)
+}
@@ -1,47 +1,129 @@
 package treemap
 
 import (
-	"strings"
+	"context"
 
 	"github.com/schollz/progressbar/v3"
 )
 
 // SumSizeImputer will set sum of children into empty parents and fill children with contant.
+// Parents without heat get size-weighted average of heat of their children.
 type SumSizeImputer struct {
 	EmptyLeafSize float64
+	Progress      Progress // no progress is reported when not set
 }
 
 func (s SumSizeImputer) ImputeSize(t Tree) {
-	// Create progress bar with total number of nodes
-	bar := progressbar.Default(int64(len(t.Nodes)))
-	bar.Describe("Imputing sizes")
+	s.ImputeSizeContext(context.Background(), t)
+}
+
+// ImputeSizeContext is same as ImputeSize, but stops with context error when context is done.
+// Tree is partially imputed then.
+func (s SumSizeImputer) ImputeSizeContext(ctx context.Context, t Tree) error {
+	bar := ProgressOrNop(s.Progress)
+	bar.Start("Imputing sizes", int64(len(t.Nodes)))
+	defer bar.Finish()
 
-	s.ImputeSizeNode(t, t.Root, bar)
+	return s.imputeSizeNode(ctx, t, t.Root, bar)
 }
 
+// ImputeSizeNode imputes sizes in subtree of node, bar is advanced for each node when it is not nil.
 func (s SumSizeImputer) ImputeSizeNode(t Tree, node string, bar *progressbar.ProgressBar) {
-	var sum float64
+	s.imputeSizeNode(context.Background(), t, node, &BarProgress{bar: bar})
+}
+
+func (s SumSizeImputer) imputeSizeNode(ctx context.Context, t Tree, node string, bar Progress) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var sum, heatSum, heatSize float64
 	for _, child := range t.To[node] {
-		s.ImputeSizeNode(t, child, bar)
-		sum += t.Nodes[child].Size
+		if err := s.imputeSizeNode(ctx, t, child, bar); err != nil {
+			return err
+		}
+
+		c := t.Nodes[child]
+		sum += c.Size
+		if c.HasHeat {
+			heatSum += c.Heat * c.Size
+			heatSize += c.Size
+		}
 	}
 
-	if n, ok := t.Nodes[node]; !ok || n.Size == 0 {
+	n, ok := t.Nodes[node]
+	if !ok || n.Size == 0 {
 		v := s.EmptyLeafSize
 		if len(t.To[node]) > 0 {
 			v = sum
 		}
 
-		var name string
-		if parts := strings.Split(node, "/"); len(parts) > 0 {
-			name = parts[len(parts)-1]
+		// name of node that is not collapsed is kept, such as label from input
+		name := n.Name
+		if name == "" || n.Path != node {
+			name = t.Format.Base(node)
 		}
 
-		t.Nodes[node] = Node{
-			Path: node,
-			Name: name,
-			Size: v,
+		n = Node{
+			Path:    node,
+			Name:    name,
+			Size:    v,
+			Heat:    n.Heat,
+			HasHeat: n.HasHeat,
 		}
 	}
+
+	if !n.HasHeat && heatSize > 0 {
+		n.Heat = heatSum / heatSize
+		n.HasHeat = true
+	}
+
+	t.Nodes[node] = n
 	bar.Add(1)
+
+	return nil
+}
+
+// ImputeSizeCompact imputes sizes and heat same as ImputeSize, but for compact tree.
+func (s SumSizeImputer) ImputeSizeCompact(t *CompactTree) {
+	s.ImputeSizeCompactContext(context.Background(), t)
+}
+
+// ImputeSizeCompactContext is same as ImputeSizeCompact, but stops with context error when context is done.
+func (s SumSizeImputer) ImputeSizeCompactContext(ctx context.Context, t *CompactTree) error {
+	bar := ProgressOrNop(s.Progress)
+	bar.Start("Imputing sizes", int64(len(t.Nodes)))
+	defer bar.Finish()
+
+	// children have higher index than parents, so all children are imputed before their parent
+	for i := len(t.Nodes) - 1; i >= 0; i-- {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var sum, heatSum, heatSize float64
+		for _, child := range t.ChildrenOf(int32(i)) {
+			c := t.Nodes[child]
+			sum += c.Size
+			if c.HasHeat {
+				heatSum += c.Heat * c.Size
+				heatSize += c.Size
+			}
+		}
+
+		n := &t.Nodes[i]
+		if n.Size == 0 {
+			n.Size = s.EmptyLeafSize
+			if n.NumChildren > 0 {
+				n.Size = sum
+			}
+		}
+		if !n.HasHeat && heatSize > 0 {
+			n.Heat = heatSum / heatSize
+			n.HasHeat = true
+		}
+		bar.Add(1)
+	}
+
+	return nil
 }
@@ -0,0 +1,103 @@
+package treemap
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSumSizeImputerHeat(t *testing.T) {
+	tree := Tree{
+		Nodes: map[string]Node{
+			"a":     {Path: "a"},
+			"a/b":   {Path: "a/b", Size: 1, Heat: 2, HasHeat: true},
+			"a/c":   {Path: "a/c", Size: 3, Heat: 6, HasHeat: true},
+			"a/d":   {Path: "a/d", Size: 4},
+			"a/e":   {Path: "a/e"},
+			"a/e/f": {Path: "a/e/f", Size: 2},
+		},
+		To: map[string][]string{
+			"a":   {"a/b", "a/c", "a/d", "a/e"},
+			"a/e": {"a/e/f"},
+		},
+		Root: "a",
+	}
+
+	SumSizeImputer{EmptyLeafSize: 1}.ImputeSize(tree)
+
+	expNodes := map[string]Node{
+		"a":     {Path: "a", Name: "a", Size: 10, Heat: 5, HasHeat: true},
+		"a/b":   {Path: "a/b", Size: 1, Heat: 2, HasHeat: true},
+		"a/c":   {Path: "a/c", Size: 3, Heat: 6, HasHeat: true},
+		"a/d":   {Path: "a/d", Size: 4},
+		"a/e":   {Path: "a/e", Name: "e", Size: 2},
+		"a/e/f": {Path: "a/e/f", Size: 2},
+	}
+	for k, exp := range expNodes {
+		if got := tree.Nodes[k]; got != exp {
+			t.Errorf("%s: exp(%#v) != got(%#v)", k, exp, got)
+		}
+	}
+}
+
+func TestSumSizeImputerNames(t *testing.T) {
+	tree := Tree{
+		Nodes: map[string]Node{
+			"r":       {Path: "r", Name: "r"},
+			"r/a":     {Path: "r/a", Name: "a"},
+			"r/a/b":   {Path: "r/a/b", Name: "b"},
+			"r/a/b/x": {Path: "r/a/b/x", Name: "x", Size: 1},
+			"r/a/b/y": {Path: "r/a/b/y", Name: "Label"},
+		},
+		To: map[string][]string{
+			"r":     {"r/a"},
+			"r/a":   {"r/a/b"},
+			"r/a/b": {"r/a/b/x", "r/a/b/y"},
+		},
+		Root: "r",
+	}
+
+	CollapseLongPaths(&tree)
+	SumSizeImputer{EmptyLeafSize: 1}.ImputeSize(tree)
+
+	// collapsed chain without size is named by last part of its path, label is kept
+	for path, exp := range map[string]string{"r": "r", "r/a/b/x": "x", "r/a/b/y": "Label"} {
+		if got := tree.Nodes[path].Name; got != exp {
+			t.Errorf("%s: exp(%s) != got(%s)", path, exp, got)
+		}
+	}
+}
+
+func TestPassesContextCanceled(t *testing.T) {
+	tree := Tree{
+		Nodes: map[string]Node{
+			"a":   {Path: "a", Name: "a"},
+			"a/b": {Path: "a/b", Name: "b", Size: 1},
+		},
+		To:   map[string][]string{"a": {"a/b"}},
+		Root: "a",
+	}
+
+	b := NewCompactTreeBuilder()
+	b.Add(Node{Path: "a/b", Size: 1})
+	compact := b.Build()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	errs := map[string]error{
+		"impute":           SumSizeImputer{}.ImputeSizeContext(ctx, tree),
+		"impute compact":   SumSizeImputer{}.ImputeSizeCompactContext(ctx, compact),
+		"collapse":         CollapseLongPathsContext(ctx, &tree, nil),
+		"collapse compact": CollapseLongPathsCompactContext(ctx, compact, nil),
+	}
+	for name, err := range errs {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: exp(%v) != got(%v)", name, context.Canceled, err)
+		}
+	}
+
+	if n := tree.Nodes["a/b"]; n.Size != 1 || n.Name != "b" {
+		t.Errorf("tree changed: %#v", n)
+	}
+}
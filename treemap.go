@@ -1,49 +1,48 @@
 package treemap
 
-import (
-	"fmt"
-	"strings"
-
-	"github.com/schollz/progressbar/v3"
-)
+import "context"
 
 type Node struct {
-	Path string
-	Name string
-	Size float64
+	Path    string
+	Name    string
+	Size    float64
+	Heat    float64
+	HasHeat bool // false when heat is missing in input and could not be imputed
 }
 
 type Tree struct {
 	Nodes map[string]Node     // node identifier (path) -> Node
 	To    map[string][]string // node identifier (path) -> list of node identifiers (paths) for edges from it (to children)
 	Root  string
+
+	Format PathFormat // how paths are split into parts, "/" without escaping when not set
 }
 
 // SetNamesFromPaths will update each node to its path leaf as name.
 func SetNamesFromPaths(t *Tree) {
+	SetNamesFromPathsContext(context.Background(), t, nil)
+}
+
+// SetNamesFromPathsContext is same as SetNamesFromPaths, but reports progress when it is not nil,
+// and stops with context error when context is done. Names are partially updated then.
+func SetNamesFromPathsContext(ctx context.Context, t *Tree, progress Progress) error {
 	if t == nil {
-		return
+		return nil
 	}
 
-	// Create progress bar with total number of nodes
-	bar := progressbar.Default(int64(len(t.Nodes)))
-	bar.Describe("Updating node names")
+	bar := ProgressOrNop(progress)
+	bar.Start("Updating node names", int64(len(t.Nodes)))
+	defer bar.Finish()
 
 	for path, node := range t.Nodes {
-		parts := strings.Split(node.Path, "/")
-		if len(parts) == 0 {
-			fmt.Println("no parts", node.Path)
-			continue
+		if err := ctx.Err(); err != nil {
+			return err
 		}
 
-		t.Nodes[path] = Node{
-			Path: node.Path,
-			Name: parts[len(parts)-1],
-			Size: node.Size,
-		}
+		node.Name = t.Format.Base(node.Path)
+		t.Nodes[path] = node
 		bar.Add(1)
 	}
 
-	// Finish the progress bar
-	bar.Finish()
+	return nil
 }